@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/amsatrio/gin_notes/initializer"
+)
+
+// FieldType describes how a field type is written in the model. Gorm is the
+// gorm tag, empty when gorm picks the column type of each dialect.
+type FieldType struct {
+	GoType string
+	Gorm   string
+	Sized  bool
+}
+
+var fieldTypes = map[string]FieldType{
+	"string": {GoType: "string", Sized: true},
+	"text":   {GoType: "string", Gorm: "type:text"},
+	"int":    {GoType: "int"},
+	"uint":   {GoType: "uint"},
+	"float":  {GoType: "float64"},
+	"bool":   {GoType: "bool"},
+	"time":   {GoType: "response.JSONTime"},
+	"bytes":  {GoType: "[]byte"},
+}
+
+const defaultStringSize = 255
+
+var identifier = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// auditColumns are added to every entity by the templates.
+var auditColumns = map[string]bool{
+	"id":          true,
+	"created_by":  true,
+	"created_on":  true,
+	"modified_by": true,
+	"modified_on": true,
+	"deleted_by":  true,
+	"deleted_on":  true,
+	"is_delete":   true,
+}
+
+type Field struct {
+	Column string
+	Type   string
+	Size   int
+}
+
+func (f Field) Name() string {
+	return pascalCase(f.Column)
+}
+
+func (f Field) JSONName() string {
+	name := f.Name()
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+func (f Field) GoType() string {
+	return fieldTypes[f.Type].GoType
+}
+
+// Tags returns the struct tags in the order used by the models.
+func (f Field) Tags() string {
+	fieldType := fieldTypes[f.Type]
+	json := f.JSONName()
+	tags := fmt.Sprintf(`form:"%s" json:"%s" xml:"%s"`, json, json, json)
+
+	if fieldType.Sized {
+		tags += fmt.Sprintf(` gorm:"size:%d" binding:"max=%d"`, f.Size, f.Size)
+	} else if fieldType.Gorm != "" {
+		tags += fmt.Sprintf(` gorm:"%s"`, fieldType.Gorm)
+	}
+	if f.Type == "time" {
+		tags += ` swaggertype:"string" example:"2024-02-16 10:33:10"`
+	}
+	return tags
+}
+
+type Entity struct {
+	Table  string
+	Name   string
+	Var    string
+	Fields []Field
+}
+
+func NewEntity(table string, fields []Field) Entity {
+	name := pascalCase(table)
+	return Entity{
+		Table:  table,
+		Name:   name,
+		Var:    strings.ToLower(name[:1]) + name[1:],
+		Fields: fields,
+	}
+}
+
+// UpdatableFields is the update whitelist of the service.
+func (e Entity) UpdatableFields() string {
+	names := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		names = append(names, strconv.Quote(field.Name()))
+	}
+	return strings.Join(names, ", ")
+}
+
+// parseFields parses "name:type[:size],..." e.g. "title:string:200,body:text".
+func parseFields(list string) ([]Field, error) {
+	var fields []Field
+	seen := map[string]bool{}
+
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("field %q must be name:type[:size]", item)
+		}
+
+		field := Field{Column: strings.TrimSpace(parts[0]), Type: strings.TrimSpace(parts[1])}
+		fieldType, ok := fieldTypes[field.Type]
+		if !ok {
+			return nil, fmt.Errorf("field %q: unknown type %s, use one of %s", item, field.Type, fieldTypeNames())
+		}
+		if !identifier.MatchString(field.Column) {
+			return nil, fmt.Errorf("field %q: name must be snake_case", item)
+		}
+		if auditColumns[field.Column] {
+			return nil, fmt.Errorf("field %q: %s is added to every entity", item, field.Column)
+		}
+		if seen[field.Column] {
+			return nil, fmt.Errorf("field %q: duplicate name", item)
+		}
+		seen[field.Column] = true
+
+		if len(parts) == 3 {
+			if !fieldType.Sized {
+				return nil, fmt.Errorf("field %q: type %s has no size", item, field.Type)
+			}
+			size, err := strconv.Atoi(parts[2])
+			if err != nil || size <= 0 {
+				return nil, fmt.Errorf("field %q: size must be a positive number", item)
+			}
+			field.Size = size
+		} else if fieldType.Sized {
+			field.Size = defaultStringSize
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+type tableColumn struct {
+	ColumnName             string
+	DataType               string
+	ColumnType             string
+	CharacterMaximumLength *int64
+}
+
+// readTableFields reads the columns of table from the MySQL database of
+// DB_URL.
+func readTableFields(table string) ([]Field, error) {
+	initializer.LoadEnvironmentVariables()
+	initializer.ConnectToDB()
+
+	var columns []tableColumn
+	result := initializer.DB.Raw(
+		"SELECT column_name AS column_name, data_type AS data_type, column_type AS column_type, "+
+			"character_maximum_length AS character_maximum_length "+
+			"FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? "+
+			"ORDER BY ordinal_position", table).Scan(&columns)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s not found", table)
+	}
+
+	var fields []Field
+	for _, column := range columns {
+		name := strings.ToLower(column.ColumnName)
+		if auditColumns[name] {
+			continue
+		}
+
+		field := Field{Column: name}
+		switch strings.ToLower(column.DataType) {
+		case "char", "varchar":
+			field.Type = "string"
+			field.Size = defaultStringSize
+			if column.CharacterMaximumLength != nil {
+				field.Size = int(*column.CharacterMaximumLength)
+			}
+		case "tinytext", "text", "mediumtext", "longtext", "json", "enum", "set":
+			field.Type = "text"
+		case "tinyint":
+			field.Type = "int"
+			if strings.ToLower(column.ColumnType) == "tinyint(1)" {
+				field.Type = "bool"
+			}
+		case "bit", "boolean":
+			field.Type = "bool"
+		case "smallint", "mediumint", "int", "integer":
+			field.Type = "int"
+		case "bigint":
+			field.Type = "uint"
+		case "decimal", "float", "double":
+			field.Type = "float"
+		case "date", "datetime", "timestamp":
+			field.Type = "time"
+		case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+			field.Type = "bytes"
+		default:
+			return nil, fmt.Errorf("column %s: data type %s is not supported", column.ColumnName, column.DataType)
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+func fieldTypeNames() string {
+	names := make([]string, 0, len(fieldTypes))
+	for name := range fieldTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// pascalCase converts snake_case to PascalCase, e.g. mobile_phone to
+// MobilePhone.
+func pascalCase(name string) string {
+	var builder strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		builder.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return builder.String()
+}
@@ -0,0 +1,97 @@
+// Command gen scaffolds a CRUD entity: the model, the service, the
+// controller and its route registration.
+//
+//	go run ./cmd/gen -table m_category -fields "name:string:100,level:int,active:bool"
+//	go run ./cmd/gen -table m_category -db
+//
+// The model and the service are only written when they do not exist yet, so
+// they can be edited by hand (-force overwrites them). The controller is
+// written to controller/<table>_api_gen.go and the route is added between
+// the gen:begin and gen:end markers of route/routes.go; both are safe to
+// regenerate. Run `make swag_init` afterwards to update the swagger docs.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	table := flag.String("table", "", "table name, e.g. m_category")
+	fields := flag.String("fields", "", "comma separated name:type[:size] list, types: "+fieldTypeNames())
+	fromDB := flag.Bool("db", false, "read the fields of -table from the database of DB_URL")
+	force := flag.Bool("force", false, "overwrite the model and the service when they exist")
+	dir := flag.String("dir", ".", "root directory of the module")
+	flag.Parse()
+
+	if err := run(*dir, *table, *fields, *fromDB, *force); err != nil {
+		fmt.Fprintln(os.Stderr, "gen: "+err.Error())
+		os.Exit(1)
+	}
+}
+
+func run(dir string, table string, fieldList string, fromDB bool, force bool) error {
+	if !identifier.MatchString(table) {
+		return fmt.Errorf("-table %q must be snake_case", table)
+	}
+
+	var fields []Field
+	var err error
+	switch {
+	case fromDB && fieldList != "":
+		return fmt.Errorf("-db and -fields can not be used together")
+	case fromDB:
+		fields, err = readTableFields(table)
+	default:
+		fields, err = parseFields(fieldList)
+	}
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("no fields, use -fields or -db")
+	}
+
+	entity := NewEntity(table, fields)
+
+	// hand written controllers are not replaced by a generated one
+	if _, err := os.Stat(filepath.Join(dir, "controller", table+"_api.go")); err == nil {
+		return fmt.Errorf("controller/%s_api.go exists, %s is not a generated entity", table, table)
+	}
+
+	files := []struct {
+		path      string
+		template  string
+		overwrite bool
+	}{
+		{filepath.Join(dir, "model", table+".go"), "model.go.tmpl", force},
+		{filepath.Join(dir, "service", table+"_service.go"), "service.go.tmpl", force},
+		{filepath.Join(dir, "controller", table+"_api_gen.go"), "controller.go.tmpl", true},
+	}
+	for _, file := range files {
+		written, err := writeTemplate(file.path, file.template, entity, file.overwrite)
+		if err != nil {
+			return err
+		}
+		if written {
+			fmt.Println("write " + file.path)
+		} else {
+			fmt.Println("skip  " + file.path + " (exists)")
+		}
+	}
+
+	routes := filepath.Join(dir, "route", "routes.go")
+	added, err := registerRoute(routes, entity)
+	if err != nil {
+		return err
+	}
+	if added {
+		fmt.Println("route " + routes)
+	}
+
+	fmt.Println("run `make migrate ARGS=\"create create_" + table + "\"` to add a migration for the table")
+	fmt.Println("run `make swag_init` to update the swagger docs")
+	return nil
+}
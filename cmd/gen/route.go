@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+const (
+	routeBegin = "// gen:begin"
+	routeEnd   = "// gen:end"
+)
+
+// registerRoute adds the RegisterResource call of entity between the
+// gen:begin and gen:end markers of path. It returns false when the route is
+// already registered anywhere in the file.
+func registerRoute(path string, entity Entity) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	source := string(data)
+
+	call := `controller.RegisterResource(v1, "` + entity.Table + `", controller.` + entity.Name + `Resource)`
+	if strings.Contains(source, call) {
+		return false, nil
+	}
+
+	begin := strings.Index(source, routeBegin)
+	end := strings.Index(source, routeEnd)
+	if begin < 0 || end < begin {
+		return false, errors.New(path + " has no " + routeBegin + " / " + routeEnd + " markers")
+	}
+
+	// indent the call like the end marker
+	lineStart := strings.LastIndex(source[:end], "\n") + 1
+	indent := source[lineStart:end]
+	if strings.TrimSpace(indent) != "" {
+		return false, errors.New(path + ": " + routeEnd + " must be on its own line")
+	}
+
+	source = source[:lineStart] + indent + call + "\n" + source[lineStart:]
+	return true, os.WriteFile(path, []byte(source), 0644)
+}
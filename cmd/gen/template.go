@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"go/format"
+	"os"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+var templates = template.Must(template.ParseFS(templateFiles, "templates/*.tmpl"))
+
+// writeTemplate executes the template name into path. An existing file is
+// kept unless overwrite is set.
+func writeTemplate(path string, name string, entity Entity, overwrite bool) (bool, error) {
+	if !overwrite {
+		if _, err := os.Stat(path); err == nil {
+			return false, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return false, err
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := templates.ExecuteTemplate(&buffer, name, entity); err != nil {
+		return false, err
+	}
+	source, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return false, err
+	}
+
+	return true, os.WriteFile(path, source, 0644)
+}
@@ -16,8 +16,20 @@ var (
 
 	ErrorAuthorizationTokenExpired = errors.New("authorization token is expired")
 
+	ErrorAuthorizationTokenRevoked = errors.New("authorization token is revoked")
+
 	ErrorAuthenticationFailed = errors.New("authentication failed")
 
+	ErrorAccountLocked = errors.New("account is locked")
+
+	ErrorPasswordExpired = errors.New("password is expired")
+
+	ErrorTokenInvalid = errors.New("token is invalid or expired")
+
+	ErrorTokenReused = errors.New("token is reused, the session is revoked")
+
+	ErrorSharePasswordInvalid = errors.New("share link password is invalid")
+
 	ErrorPermissionDenied = errors.New("permission is denied")
 
 	ErrorUserNotFound = errors.New("user not found")
@@ -33,4 +45,6 @@ var (
 	ErrorSaveDataFailed = errors.New("failed to save data")
 
 	ErrorRedisDeleteFailed = errors.New("failed to delete data in redis")
+
+	ErrorRedisReadFailed = errors.New("failed to read data in redis")
 )
@@ -1,12 +1,14 @@
 package controller
 
 import (
+	"context"
+	"errors"
 	"net/http"
-	"os"
 	"time"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 
 	"github.com/amsatrio/gin_notes/constant"
 	"github.com/amsatrio/gin_notes/initializer"
@@ -36,24 +38,29 @@ func JwtLogin(c *gin.Context) {
 
 	// login logic
 	jwtService := service.NewJwtServiceImpl(initializer.DB)
-	authorities, err := jwtService.JwtAuthenticate(c, body)
-	if err != nil {
-		c.Set(constant.ERROR_KEY, constant.ErrorAuthenticationFailed)
-		c.Set(constant.ERROR_MESSAGE, err.Error())
+	mUser, err := jwtService.JwtAuthenticate(c, body)
+	if errors.Is(err, constant.ErrorAccountLocked) {
+		c.Set(constant.ERROR_KEY, constant.ErrorAccountLocked)
+		c.Set(constant.ERROR_MESSAGE, "too many failed logins, try again later or ask an admin to unlock the account")
+		c.Abort()
+		return
+	}
+	if errors.Is(err, constant.ErrorPasswordExpired) {
+		c.Set(constant.ERROR_KEY, constant.ErrorPasswordExpired)
+		c.Set(constant.ERROR_MESSAGE, "the password is too old, set a new one with forgot_password")
 		c.Abort()
 		return
 	}
-
-	// generate token
-	token, err := util.JwtGenerateMainToken(body.Username, authorities)
 	if err != nil {
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_KEY, constant.ErrorAuthenticationFailed)
 		c.Set(constant.ERROR_MESSAGE, err.Error())
 		c.Abort()
 		return
 	}
-	// generate refresh token
-	refreshToken, err := util.JwtGenerateRefreshToken(body.Username, authorities)
+
+	// generate token
+	refreshTokenService := service.NewRefreshTokenServiceImpl(initializer.DB)
+	responseAuth, err := refreshTokenService.Issue(c, mUser)
 	if err != nil {
 		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
 		c.Set(constant.ERROR_MESSAGE, err.Error())
@@ -63,18 +70,12 @@ func JwtLogin(c *gin.Context) {
 
 	// save token to session
 	session := sessions.Default(c)
-	session.Set("auth_token", token)
+	session.Set("auth_token", responseAuth.Token)
 	err = session.Save()
 	if err != nil {
 		util.Log("ERROR", "controllers", "JwtLogin", "save token to session error: "+err.Error())
 	}
 
-	responseAuth := &response.ResponseAuth{
-		Token:        token,
-		RefreshToken: refreshToken,
-		ExpiredIn:    os.Getenv("AUTH_JWT_TOKEN_EXPIRED_MS"),
-	}
-
 	res := &response.Response{}
 	res.Timestamp = response.JSONTime{Time: time.Now()}
 	res.Data = responseAuth
@@ -87,6 +88,41 @@ func JwtLogin(c *gin.Context) {
 
 func JwtLogout(c *gin.Context) {
 	session := sessions.Default(c)
+
+	var claims jwt.MapClaims
+	if token, ok := session.Get("auth_token").(string); ok {
+		claims, _ = util.JwtExtractAllClaims(token, "main_token")
+	}
+
+	// revoke the access token until it expires
+	if claims != nil {
+		tokenDenylistService := service.NewTokenDenylistServiceImpl(initializer.RDB)
+		err := tokenDenylistService.Deny(c, util.JwtGetTokenId(claims), util.JwtGetExpiration(claims))
+		if err != nil {
+			util.Log("ERROR", "controllers", "JwtLogout", "deny token error: "+err.Error())
+			c.Set(constant.ERROR_KEY, constant.ErrorSaveDataFailed)
+			c.Set(constant.ERROR_MESSAGE, err.Error())
+			c.Abort()
+			return
+		}
+	}
+
+	// revoke the refresh tokens of the session of the access token
+	sessionId := c.GetString("session_id")
+	if claims != nil && sessionId == "" {
+		sessionId = util.JwtGetSessionId(claims)
+	}
+	if sessionId != "" {
+		refreshTokenService := service.NewRefreshTokenServiceImpl(initializer.DB)
+		if err := refreshTokenService.RevokeSession(c, sessionId); err != nil {
+			util.Log("ERROR", "controllers", "JwtLogout", "revoke session error: "+err.Error())
+			c.Set(constant.ERROR_KEY, constant.ErrorSaveDataFailed)
+			c.Set(constant.ERROR_MESSAGE, err.Error())
+			c.Abort()
+			return
+		}
+	}
+
 	session.Delete("auth_token")
 	session.Save()
 	res := &response.Response{}
@@ -127,8 +163,21 @@ func JwtRefreshToken(c *gin.Context) {
 	}
 	_ = claims
 
-	// verify refresh token
-	refreshClaims, err := util.JwtExtractAllClaims(body.RefreshToken, "refresh_token")
+	// rotate refresh token
+	refreshTokenService := service.NewRefreshTokenServiceImpl(initializer.DB)
+	responseAuth, err := refreshTokenService.Rotate(c, body.RefreshToken)
+	if errors.Is(err, constant.ErrorTokenReused) {
+		c.Set(constant.ERROR_KEY, constant.ErrorTokenReused)
+		c.Set(constant.ERROR_MESSAGE, "log in again")
+		c.Abort()
+		return
+	}
+	if errors.Is(err, constant.ErrorTokenInvalid) {
+		c.Set(constant.ERROR_KEY, constant.ErrorAuthenticationFailed)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
 	if err != nil {
 		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
 		c.Set(constant.ERROR_MESSAGE, err.Error())
@@ -136,43 +185,129 @@ func JwtRefreshToken(c *gin.Context) {
 		return
 	}
 
-	// re-generate token
-	authorities := util.JwtGetAuthorities(refreshClaims)
+	// save token to session
+	session := sessions.Default(c)
+	session.Set("auth_token", responseAuth.Token)
+	if err := session.Save(); err != nil {
+		util.Log("ERROR", "controllers", "JwtRefreshToken", "save token to session error: "+err.Error())
+	}
 
-	subject, err := refreshClaims.GetSubject()
+	res := &response.ResponseTimestamp{}
+	res.Timestamp = time.Now()
+	res.Data = responseAuth
+	res.Status = http.StatusOK
+	res.Message = "success"
+	res.Path = c.FullPath()
+
+	c.JSON(res.Status, res)
+}
+
+func JwtForgotPassword(c *gin.Context) {
+	body := request.RequestForgotPassword{}
+	err := c.ShouldBindBodyWithJSON(&body)
 	if err != nil {
+		out, _ := util.ValidateError(err)
+		if out != nil {
+			c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+			c.Set(constant.ERROR_MESSAGE, out)
+			c.Abort()
+			return
+		}
+		util.Log("ERROR", "controllers", "JwtForgotPassword", "bind error: "+err.Error())
 		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
 		c.Set(constant.ERROR_MESSAGE, err.Error())
 		c.Abort()
 		return
 	}
-	token, err := util.JwtGenerateMainToken(subject, authorities)
+
+	// the reply neither waits for nor tells the outcome, so it is the same
+	// for a known and an unknown email
+	passwordResetService := service.NewPasswordResetServiceImpl(initializer.DB, util.GetMailer())
+	go func(email string) {
+		if err := passwordResetService.ForgotPassword(context.Background(), email); err != nil {
+			util.Log("ERROR", "controllers", "JwtForgotPassword", "error: "+err.Error())
+		}
+	}(body.Email)
+
+	res := &response.Response{}
+	res.Timestamp = response.JSONTime{Time: time.Now()}
+	res.Data = nil
+	res.Status = http.StatusOK
+	res.Message = "if the email is registered, a reset password mail has been sent"
+	res.Path = c.FullPath()
+
+	c.JSON(res.Status, res)
+}
+
+func JwtResetPassword(c *gin.Context) {
+	body := request.RequestResetPassword{}
+	err := c.ShouldBindBodyWithJSON(&body)
 	if err != nil {
+		out, _ := util.ValidateError(err)
+		if out != nil {
+			c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+			c.Set(constant.ERROR_MESSAGE, out)
+			c.Abort()
+			return
+		}
+		util.Log("ERROR", "controllers", "JwtResetPassword", "bind error: "+err.Error())
 		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
 		c.Set(constant.ERROR_MESSAGE, err.Error())
 		c.Abort()
 		return
 	}
 
-	// save token to session
-	session := sessions.Default(c)
-	session.Set("auth_token", token)
-	if err := session.Save(); err != nil {
-		util.Log("ERROR", "controllers", "JwtRefreshToken", "save token to session error: "+err.Error())
+	passwordResetService := service.NewPasswordResetServiceImpl(initializer.DB, util.GetMailer())
+	err = passwordResetService.ResetPassword(c, body.Token, body.Password)
+	if out := util.ValidateFieldError(err); out != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, out)
+		c.Abort()
+		return
 	}
-
-	responseAuth := &response.ResponseAuth{
-		Token:        token,
-		RefreshToken: body.RefreshToken,
-		ExpiredIn:    os.Getenv("AUTH_JWT_TOKEN_EXPIRED_MS"),
+	if errors.Is(err, constant.ErrorTokenInvalid) {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
+	if err != nil {
+		util.Log("ERROR", "controllers", "JwtResetPassword", "error: "+err.Error())
+		c.Set(constant.ERROR_KEY, constant.ErrorSaveDataFailed)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
 	}
 
-	res := &response.ResponseTimestamp{}
-	res.Timestamp = time.Now()
-	res.Data = responseAuth
+	res := &response.Response{}
+	res.Timestamp = response.JSONTime{Time: time.Now()}
+	res.Data = nil
 	res.Status = http.StatusOK
 	res.Message = "success"
 	res.Path = c.FullPath()
 
 	c.JSON(res.Status, res)
 }
+
+// JwtJwks godoc
+//
+//	@Summary		JwtJwks
+//	@Description	Public keys of the tokens as a JSON Web Key Set
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	map[string]interface{}
+//	@Failure		500	{object}	response.Response
+//	@Router			/.well-known/jwks.json [get]
+func JwtJwks(c *gin.Context) {
+	keyring, err := util.GetJwtKeyring()
+	if err != nil {
+		util.Log("ERROR", "controllers", "JwtJwks", "load keys error: "+err.Error())
+		c.Set(constant.ERROR_KEY, constant.ErrorRetrieveDataFailed)
+		c.Abort()
+		return
+	}
+
+	// a verifier caches the set, a rotation publishes the next key ahead
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, keyring.Jwks())
+}
@@ -1,29 +1,34 @@
 package controller
 
 import (
-	"encoding/json"
 	"errors"
-	"net/http"
-	"regexp"
 	"strconv"
-	"time"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
 	"github.com/amsatrio/gin_notes/constant"
 	"github.com/amsatrio/gin_notes/initializer"
+	"github.com/amsatrio/gin_notes/middleware"
 	"github.com/amsatrio/gin_notes/model"
 	"github.com/amsatrio/gin_notes/model/request"
-	"github.com/amsatrio/gin_notes/model/response"
 	"github.com/amsatrio/gin_notes/service"
 	"github.com/amsatrio/gin_notes/util"
 )
 
+// MNotesResource keeps every user to the notes they own, sharedMNotes opens
+// the ones shared with them.
+var MNotesResource = NewResource(ResourceOptions[model.MNotes]{
+	Service:    service.NewMNotesCrudService,
+	Permission: "notes",
+	Owned:      true,
+})
+
 // MNotesPage godoc
 //
 //	@Summary		MNotesPage
-//	@Description	Get Page MNotes
+//	@Description	Get Page MNotes of the user, or the ones shared with the user
 //	@Tags			mNotes
 //	@Accept			json
 //	@Produce		json
@@ -31,96 +36,24 @@ import (
 //	@Param			_page	query		string	false	"page" default(0)
 //	@Param			_size	query		string	false	"size" default(5)
 //	@Param			_sort	query		string	false	"sort"
-//	@Param			_filter	query		string	false	"filter"
+//	@Param			_filter	query		string	false	"filter, a JSON list of {id, value, matchMode, dataType, mode} or a group {mode, filters}. mode (AND or OR) joins a condition with the ones before it, on a group it joins the filters of the group"
 //	@Param			_q	query		string	false	"global filter"
+//	@Param			_cursor	query		bool	false	"keyset pagination"
+//	@Param			_after	query		string	false	"cursor of the next page"
+//	@Param			_before	query		string	false	"cursor of the previous page"
+//	@Param			_count	query		string	false	"EXACT, ESTIMATE or NONE" default(EXACT)
+//	@Param			_includeDeleted	query		bool	false	"include soft-deleted data, needs deleted:read"
+//	@Param			_onlyDeleted	query		bool	false	"only soft-deleted data, needs deleted:read"
+//	@Param			_allOwners	query		bool	false	"notes of every user, needs notes:all_owners"
+//	@Param			_view	query		string	false	"mine or shared (with me)" default(mine)
 //	@Success		200	{object}	response.Response
 //	@Failure		400	{object}	response.Response
+//	@Failure		403	{object}	response.Response
 //	@Failure		404	{object}	response.Response
 //	@Failure		500	{object}	response.Response
 //	@Router			/v1/m_notes [get]
 func MNotesPage(c *gin.Context) {
-	sortRequest := c.DefaultQuery("_sort", "[]")
-	pageRequest := c.DefaultQuery("_page", "0")
-	sizeRequest := c.DefaultQuery("_size", "10")
-	filterRequest := c.DefaultQuery("_filter", "[]")
-	searchRequest := c.DefaultQuery("_q", "")
-
-	pageInt, errorPageInt := strconv.Atoi(pageRequest)
-	sizeInt64, errorLimitInt64 := strconv.ParseInt(sizeRequest, 10, 64)
-	sizeInt, errorLimitInt := strconv.Atoi(sizeRequest)
-
-	if errorPageInt != nil {
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, errorPageInt.Error())
-		c.Abort()
-		return
-	}
-	if errorLimitInt64 != nil {
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, errorLimitInt64.Error())
-		c.Abort()
-		return
-	}
-	if errorLimitInt != nil {
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, errorLimitInt.Error())
-		c.Abort()
-		return
-	}
-
-	isLetterNumber := regexp.MustCompile(`^[a-zA-Z0-9\s]+$`).MatchString
-	if !isLetterNumber(searchRequest) && searchRequest != "" {
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, errors.New("global search must not contains special character"))
-		c.Abort()
-		return
-	}
-
-	var sorts []request.Sort
-	jsonUnmarshalErr := json.Unmarshal([]byte(sortRequest), &sorts)
-	if jsonUnmarshalErr != nil {
-		util.Log("ERROR", "controllers", "MNotesPage", "jsonUnmarshalErr error: "+jsonUnmarshalErr.Error())
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, jsonUnmarshalErr)
-		c.Abort()
-		return
-	}
-	var filters []request.Filter
-	jsonUnmarshalErr = json.Unmarshal([]byte(filterRequest), &filters)
-	if jsonUnmarshalErr != nil {
-		util.Log("ERROR", "controllers", "MNotesPage", "jsonUnmarshalErr error: "+jsonUnmarshalErr.Error())
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, jsonUnmarshalErr)
-		c.Abort()
-		return
-	}
-
-	mNotesService := service.NewMNotesServiceImpl(initializer.DB)
-	result, err := mNotesService.GetPageMNotes(
-		c,
-		sorts,
-		filters,
-		searchRequest,
-		pageInt,
-		sizeInt64,
-		sizeInt)
-
-	if err != nil {
-		util.Log("ERROR", "controllers", "MNotesPage", "error: "+err.Error())
-		c.Set(constant.ERROR_KEY, constant.ErrorRetrieveDataFailed)
-		c.Set(constant.ERROR_MESSAGE, err)
-		c.Abort()
-		return
-	}
-
-	res := &response.Response{}
-	res.Timestamp = response.JSONTime{Time: time.Now()}
-	res.Data = *result
-	res.Status = http.StatusOK
-	res.Message = "success"
-	res.Path = c.FullPath()
-
-	c.JSON(res.Status, res)
+	MNotesResource.Page(c)
 }
 
 // MNotesCreate godoc
@@ -138,60 +71,7 @@ func MNotesPage(c *gin.Context) {
 //	@Failure		500	{object}	response.Response
 //	@Router			/v1/m_notes [post]
 func MNotesCreate(c *gin.Context) {
-
-	// get request body
-	body := model.MNotes{}
-
-	// validate
-	err := c.ShouldBindJSON(&body)
-	if err != nil {
-		util.LogError("controllers", "MNotesCreate", "bind error: "+err.Error(), err)
-		out, _ := util.ValidateError(err)
-		if out != nil {
-			c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-			c.Set(constant.ERROR_MESSAGE, out)
-			c.Abort()
-			return
-		}
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, err)
-		c.Abort()
-		return
-	}
-
-	email := c.GetString("username")
-
-	// find mUser
-	mUserService := service.NewMUserServiceImpl(initializer.DB)
-	mUser, err := mUserService.GetMUserByEmail(c, email)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		util.Log("ERROR", "controllers", "MNotesCreate", "create error: "+err.Error())
-		c.Set(constant.ERROR_KEY, constant.ErrorUserNotFound)
-		c.Set(constant.ERROR_MESSAGE, err.Error())
-		c.Abort()
-		return
-	}
-
-	mNotesService := service.NewMNotesServiceImpl(initializer.DB)
-
-	err = mNotesService.CreateMNotes(c, &body, mUser)
-
-	if err != nil {
-		util.Log("ERROR", "controllers", "MNotesCreate", "create error: "+err.Error())
-		c.Set(constant.ERROR_KEY, constant.ErrorSaveDataFailed)
-		c.Set(constant.ERROR_MESSAGE, err.Error())
-		c.Abort()
-		return
-	}
-
-	res := &response.Response{}
-	res.Timestamp = response.JSONTime{Time: time.Now()}
-	res.Data = body
-	res.Status = http.StatusOK
-	res.Message = "success"
-	res.Path = c.FullPath()
-
-	c.JSON(res.Status, res)
+	MNotesResource.Create(c)
 }
 
 // MNotesUpdate godoc
@@ -204,78 +84,15 @@ func MNotesCreate(c *gin.Context) {
 //	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
 //	@Param			mNotes	body		model.MNotes	true	"Update MNotes"
 //	@Param			id	path		int	true	"MNotes id"
+//	@Param			_allOwners	query		bool	false	"notes of every user, needs notes:all_owners"
 //	@Success		200	{object}	response.Response
 //	@Failure		400	{object}	response.Response
+//	@Failure		403	{object}	response.Response
 //	@Failure		404	{object}	response.Response
 //	@Failure		500	{object}	response.Response
 //	@Router			/v1/m_notes/{id} [put]
 func MNotesUpdate(c *gin.Context) {
-
-	id := c.Param("id")
-	var idUint uint
-	idUint64, err := strconv.ParseUint(id, 10, 32)
-	if err != nil {
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, err.Error())
-		c.Abort()
-		return
-	}
-	idUint = uint(idUint64)
-
-	body := model.MNotes{}
-
-	// validate
-	err = c.ShouldBindJSON(&body)
-	if err != nil {
-		util.LogError("controllers", "MNotesUpdate", "bind error: "+err.Error(), err)
-		out, _ := util.ValidateError(err)
-		if out != nil {
-			c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-			c.Set(constant.ERROR_MESSAGE, out)
-			c.Abort()
-			return
-		}
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, err)
-		c.Abort()
-		return
-	}
-
-	body.Id = idUint
-
-	email := c.GetString("username")
-
-	// find mUser
-	mUserService := service.NewMUserServiceImpl(initializer.DB)
-	mUser, err := mUserService.GetMUserByEmail(c, email)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		util.Log("ERROR", "controllers", "MNotesUpdate", "error: "+err.Error())
-		c.Set(constant.ERROR_KEY, constant.ErrorUserNotFound)
-		c.Set(constant.ERROR_MESSAGE, err.Error())
-		c.Abort()
-		return
-	}
-
-	mNotesService := service.NewMNotesServiceImpl(initializer.DB)
-
-	err = mNotesService.UpdateMNotes(c, &body, mUser)
-
-	if err != nil {
-		util.Log("ERROR", "controllers", "MNotesUpdate UpdateMNotes", err.Error())
-		c.Set(constant.ERROR_KEY, constant.ErrorSaveDataFailed)
-		c.Set(constant.ERROR_MESSAGE, err)
-		c.Abort()
-		return
-	}
-
-	res := &response.Response{}
-	res.Timestamp = response.JSONTime{Time: time.Now()}
-	res.Data = body
-	res.Status = http.StatusOK
-	res.Message = "success"
-	res.Path = c.FullPath()
-
-	c.JSON(res.Status, res)
+	MNotesResource.Update(c)
 }
 
 // MNotesIndex godoc
@@ -287,201 +104,287 @@ func MNotesUpdate(c *gin.Context) {
 //	@Produce		json
 //	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
 //	@Param			id	path		int	true	"MNotes id"
+//	@Param			_includeDeleted	query		bool	false	"include soft-deleted data, needs deleted:read"
+//	@Param			_onlyDeleted	query		bool	false	"only soft-deleted data, needs deleted:read"
+//	@Param			_allOwners	query		bool	false	"notes of every user, needs notes:all_owners"
 //	@Success		200	{object}	response.Response
 //	@Failure		400	{object}	response.Response
+//	@Failure		403	{object}	response.Response
 //	@Failure		404	{object}	response.Response
 //	@Failure		500	{object}	response.Response
 //	@Router			/v1/m_notes/{id} [get]
 func MNotesIndex(c *gin.Context) {
+	MNotesResource.Index(c)
+}
 
-	id := c.Param("id")
-	var idUint uint
-	idUint64, err := strconv.ParseUint(id, 10, 32)
-	if err != nil {
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, err.Error())
-		c.Abort()
-		return
-	}
-	idUint = uint(idUint64)
+// MNotesDelete godoc
+//
+//	@Summary		MNotesDelete
+//	@Description	Delete MNotes by id
+//	@Tags			mNotes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			id	path		int	true	"MNotes id"
+//	@Param			_allOwners	query		bool	false	"notes of every user, needs notes:all_owners"
+//	@Success		200	{object}	response.Response
+//	@Failure		400	{object}	response.Response
+//	@Failure		403	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_notes/{id} [delete]
+func MNotesDelete(c *gin.Context) {
+	MNotesResource.Delete(c)
+}
 
-	mNotesService := service.NewMNotesServiceImpl(initializer.DB)
+// MNotesSoftDelete godoc
+//
+//	@Summary		MNotesSoftDelete
+//	@Description	Soft Delete MNotes by id
+//	@Tags			mNotes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			id	path		int	true	"MNotes id"
+//	@Param			_allOwners	query		bool	false	"notes of every user, needs notes:all_owners"
+//	@Success		200	{object}	response.Response
+//	@Failure		400	{object}	response.Response
+//	@Failure		403	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_notes/delete/{id} [put]
+func MNotesSoftDelete(c *gin.Context) {
+	MNotesResource.SoftDelete(c)
+}
 
-	mNotes, err := mNotesService.GetMNotes(c, idUint)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		c.Set(constant.ERROR_KEY, constant.ErrorDataNotFound)
-		c.Set(constant.ERROR_MESSAGE, err)
-		c.Abort()
+// MNotesRestore godoc
+//
+//	@Summary		MNotesRestore
+//	@Description	Restore MNotes by id
+//	@Tags			mNotes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			id	path		int	true	"MNotes id"
+//	@Param			_allOwners	query		bool	false	"notes of every user, needs notes:all_owners"
+//	@Success		200	{object}	response.Response
+//	@Failure		400	{object}	response.Response
+//	@Failure		403	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_notes/restore/{id} [put]
+func MNotesRestore(c *gin.Context) {
+	MNotesResource.Restore(c)
+}
+
+// MNotesHeader godoc
+//
+//	@Summary		MNotesHeader
+//	@Description	Get MNotes header
+//	@Tags			mNotes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Success		200	{object}	response.Response
+//	@Failure		400	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_notes/header [get]
+func MNotesHeader(c *gin.Context) {
+	MNotesResource.Header(c)
+}
+
+// MNotesShares godoc
+//
+//	@Summary		MNotesShares
+//	@Description	Get the shares of MNotes by id, needs the owner permission on the note
+//	@Tags			mNotes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			id	path		int	true	"MNotes id"
+//	@Param			_allOwners	query		bool	false	"notes of every user, needs notes:all_owners"
+//	@Success		200	{object}	response.Response
+//	@Failure		400	{object}	response.Response
+//	@Failure		403	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_notes/{id}/shares [get]
+func MNotesShares(c *gin.Context) {
+	idUint, ok := sharedMNotes(c, nil, "Shares")
+	if !ok {
 		return
 	}
 
+	tNoteShareService := service.NewTNoteShareServiceImpl(initializer.DB)
+	shares, err := tNoteShareService.GetTNoteShares(c, idUint)
 	if err != nil {
-		util.Log("ERROR", "controllers", "MNotesIndex", err.Error())
 		c.Set(constant.ERROR_KEY, constant.ErrorRetrieveDataFailed)
-		c.Set(constant.ERROR_MESSAGE, err)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
 		c.Abort()
 		return
 	}
 
-	res := &response.Response{}
-	res.Timestamp = response.JSONTime{Time: time.Now()}
-	res.Data = mNotes
-	res.Status = http.StatusOK
-	res.Message = "success"
-	res.Path = c.FullPath()
-
-	c.JSON(res.Status, res)
+	MNotesResource.success(c, shares)
 }
 
-// MNotesDelete godoc
+// MNotesShare godoc
 //
-//	@Summary		MNotesDelete
-//	@Description	Delete MNotes by id
+//	@Summary		MNotesShare
+//	@Description	Share MNotes by id with a user or a role, the permission of an existing share is replaced. Needs the owner permission on the note
 //	@Tags			mNotes
 //	@Accept			json
 //	@Produce		json
 //	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
 //	@Param			id	path		int	true	"MNotes id"
+//	@Param			share	body		request.RequestNoteShare	true	"Grantee and permission: view, edit or owner"
+//	@Param			_allOwners	query		bool	false	"notes of every user, needs notes:all_owners"
 //	@Success		200	{object}	response.Response
 //	@Failure		400	{object}	response.Response
+//	@Failure		403	{object}	response.Response
 //	@Failure		404	{object}	response.Response
 //	@Failure		500	{object}	response.Response
-//	@Router			/v1/m_notes/{id} [delete]
-func MNotesDelete(c *gin.Context) {
-	// get id from request param
-	idParam := c.Param("id")
-	var idUint uint
-	idUint64, err := strconv.ParseUint(idParam, 10, 32)
+//	@Router			/v1/m_notes/{id}/shares [post]
+func MNotesShare(c *gin.Context) {
+	body := request.RequestNoteShare{}
+	err := c.ShouldBindBodyWithJSON(&body)
 	if err != nil {
+		out, _ := util.ValidateError(err)
+		if out != nil {
+			c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+			c.Set(constant.ERROR_MESSAGE, out)
+			c.Abort()
+			return
+		}
 		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
 		c.Set(constant.ERROR_MESSAGE, err.Error())
 		c.Abort()
 		return
 	}
-	idUint = uint(idUint64)
 
-	email := c.GetString("username")
+	mUserAccess, ok := MNotesResource.accessUser(c, "Share")
+	if !ok {
+		return
+	}
+	idUint, ok := sharedMNotes(c, mUserAccess, "Share")
+	if !ok {
+		return
+	}
 
-	// find mUser
-	mUserService := service.NewMUserServiceImpl(initializer.DB)
-	mUser, err := mUserService.GetMUserByEmail(c, email)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		util.Log("ERROR", "controllers", "MNotesDelete", "create error: "+err.Error())
-		c.Set(constant.ERROR_KEY, constant.ErrorUserNotFound)
-		c.Set(constant.ERROR_MESSAGE, err.Error())
+	tNoteShareService := service.NewTNoteShareServiceImpl(initializer.DB)
+	share, err := tNoteShareService.GrantTNoteShare(c, idUint, body, mUserAccess)
+	if out := util.ValidateFieldError(err); out != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, out)
 		c.Abort()
 		return
 	}
-
-	mNotesService := service.NewMNotesServiceImpl(initializer.DB)
-
-	// delete mNotes
-	err = mNotesService.DeleteMNotes(c, idUint, mUser)
-
 	if err != nil {
-		c.Set(constant.ERROR_KEY, constant.ErrorDataNotFound)
+		c.Set(constant.ERROR_KEY, constant.ErrorSaveDataFailed)
 		c.Set(constant.ERROR_MESSAGE, err.Error())
 		c.Abort()
 		return
 	}
 
-	// return response
-	res := &response.Response{}
-	res.Timestamp = response.JSONTime{Time: time.Now()}
-	res.Data = nil
-	res.Status = http.StatusOK
-	res.Message = "success"
-	res.Path = c.FullPath()
-
-	c.JSON(res.Status, res)
+	evictMNotes(c)
+	MNotesResource.success(c, share)
 }
 
-// MNotesSoftDelete godoc
+// MNotesUnshare godoc
 //
-//	@Summary		MNotesSoftDelete
-//	@Description	Soft Delete MNotes by id
+//	@Summary		MNotesUnshare
+//	@Description	Revoke a share of MNotes by id, needs the owner permission on the note
 //	@Tags			mNotes
 //	@Accept			json
 //	@Produce		json
 //	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
 //	@Param			id	path		int	true	"MNotes id"
+//	@Param			shareId	path		int	true	"TNoteShare id"
+//	@Param			_allOwners	query		bool	false	"notes of every user, needs notes:all_owners"
 //	@Success		200	{object}	response.Response
 //	@Failure		400	{object}	response.Response
+//	@Failure		403	{object}	response.Response
 //	@Failure		404	{object}	response.Response
 //	@Failure		500	{object}	response.Response
-//	@Router			/v1/m_notes/delete/{id} [put]
-func MNotesSoftDelete(c *gin.Context) {
-	// get id from request param
-	id := c.Param("id")
-	var idUint uint
-	idUint64, err := strconv.ParseUint(id, 10, 32)
+//	@Router			/v1/m_notes/{id}/shares/{shareId} [delete]
+func MNotesUnshare(c *gin.Context) {
+	shareIdUint64, err := strconv.ParseUint(c.Param("shareId"), 10, 32)
 	if err != nil {
 		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, err)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
 		c.Abort()
 		return
 	}
-	idUint = uint(idUint64)
 
-	email := c.GetString("username")
+	idUint, ok := sharedMNotes(c, nil, "Unshare")
+	if !ok {
+		return
+	}
 
-	mUserService := service.NewMUserServiceImpl(initializer.DB)
-	mUser, err := mUserService.GetMUserByEmail(c, email)
+	tNoteShareService := service.NewTNoteShareServiceImpl(initializer.DB)
+	err = tNoteShareService.RevokeTNoteShare(c, idUint, uint(shareIdUint64))
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		util.Log("ERROR", "controllers", "MNotesSoftDelete", "create error: "+err.Error())
-		c.Set(constant.ERROR_KEY, constant.ErrorUserNotFound)
+		c.Set(constant.ERROR_KEY, constant.ErrorDataNotFound)
 		c.Set(constant.ERROR_MESSAGE, err.Error())
 		c.Abort()
 		return
 	}
-
-	mNotesService := service.NewMNotesServiceImpl(initializer.DB)
-
-	// delete mNotes
-	err = mNotesService.SoftDeleteMNotes(c, idUint, mUser)
-
-	// validate error
 	if err != nil {
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, err)
+		c.Set(constant.ERROR_KEY, constant.ErrorSaveDataFailed)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
 		c.Abort()
 		return
 	}
 
-	// return response
-	res := &response.Response{}
-	res.Timestamp = response.JSONTime{Time: time.Now()}
-	res.Data = nil
-	res.Status = http.StatusOK
-	res.Message = "success"
-	res.Path = c.FullPath()
+	evictMNotes(c)
+	MNotesResource.success(c, nil)
+}
 
-	c.JSON(res.Status, res)
+// evictMNotes drops the cached notes, a grantee may see more or fewer of them
+// after a share changed.
+func evictMNotes(c *gin.Context) {
+	prefix := strings.Split(c.FullPath(), "/:id/")[0]
+	if err := middleware.RedisEvict(prefix); err != nil {
+		util.Log("ERROR", "controllers", "MNotesShare", "evict cache error: "+err.Error())
+	}
 }
 
-// MNotesHeader godoc
-//
-//	@Summary		MNotesHeader
-//	@Description	Get MNotes header
-//	@Tags			mNotes
-//	@Accept			json
-//	@Produce		json
-//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
-//	@Success		200	{object}	response.Response
-//	@Failure		400	{object}	response.Response
-//	@Failure		404	{object}	response.Response
-//	@Failure		500	{object}	response.Response
-//	@Router			/v1/m_notes/header [get]
-func MNotesHeader(c *gin.Context) {
-	header := util.GetJSONFieldTypes(model.MNotes{})
+// sharedMNotes returns the id of the note of the path once the user of the
+// request is found to manage its shares.
+func sharedMNotes(c *gin.Context, mUser *model.MUser, operation string) (uint, bool) {
+	idUint, _, ok := authorizedMNotes(c, mUser, operation, service.ACCESS_SHARE)
+	return idUint, ok
+}
+
+// authorizedMNotes returns the id of the note of the path and the service of
+// the request once the user of the request is found to be allowed action on
+// it.
+func authorizedMNotes(c *gin.Context, mUser *model.MUser, operation string, action service.AccessAction) (uint, service.CrudService[model.MNotes], bool) {
+	idUint, ok := MNotesResource.paramId(c)
+	if !ok {
+		return 0, nil, false
+	}
 
-	// return response
-	res := &response.Response{}
-	res.Timestamp = response.JSONTime{Time: time.Now()}
-	res.Data = header
-	res.Status = http.StatusOK
-	res.Message = "success"
-	res.Path = c.FullPath()
+	crud, ok := MNotesResource.ownedService(c, mUser, operation)
+	if !ok {
+		return 0, nil, false
+	}
+	_, err := crud.Authorize(c, idUint, action)
+	if MNotesResource.denied(c, err) {
+		return 0, nil, false
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Set(constant.ERROR_KEY, constant.ErrorDataNotFound)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return 0, nil, false
+	}
+	if err != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRetrieveDataFailed)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return 0, nil, false
+	}
 
-	c.JSON(res.Status, res)
+	return idUint, crud, true
 }
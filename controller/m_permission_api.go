@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/service"
+)
+
+// MPermissionResource serves the permission codes that roles are granted.
+var MPermissionResource = NewResource(ResourceOptions[model.MPermission]{
+	Service:    service.NewMPermissionCrudService,
+	Permission: "permissions",
+})
+
+// MPermissionPage godoc
+//
+//	@Summary		MPermissionPage
+//	@Description	Get Page MPermission
+//	@Tags			mPermission
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			_page	query		string	false	"page" default(0)
+//	@Param			_size	query		string	false	"size" default(5)
+//	@Param			_sort	query		string	false	"sort"
+//	@Param			_filter	query		string	false	"filter, a JSON list of {id, value, matchMode, dataType, mode} or a group {mode, filters}. mode (AND or OR) joins a condition with the ones before it, on a group it joins the filters of the group"
+//	@Param			_q	query		string	false	"global filter"
+//	@Param			_cursor	query		bool	false	"keyset pagination"
+//	@Param			_after	query		string	false	"cursor of the next page"
+//	@Param			_before	query		string	false	"cursor of the previous page"
+//	@Param			_count	query		string	false	"EXACT, ESTIMATE or NONE" default(EXACT)
+//	@Param			_includeDeleted	query		bool	false	"include soft-deleted data, needs deleted:read"
+//	@Param			_onlyDeleted	query		bool	false	"only soft-deleted data, needs deleted:read"
+//	@Success		200	{object}	response.Response
+//	@Failure		400	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_permission [get]
+func MPermissionPage(c *gin.Context) {
+	MPermissionResource.Page(c)
+}
+
+// MPermissionCreate godoc
+//
+//	@Summary		MPermissionCreate
+//	@Description	Create MPermission
+//	@Tags			mPermission
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			mPermission	body		model.MPermission	true	"Add MPermission"
+//	@Success		200	{object}	response.Response
+//	@Failure		400	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_permission [post]
+func MPermissionCreate(c *gin.Context) {
+	MPermissionResource.Create(c)
+}
+
+// MPermissionUpdate godoc
+//
+//	@Summary		MPermissionUpdate
+//	@Description	Update MPermission
+//	@Tags			mPermission
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			mPermission	body		model.MPermission	true	"Update MPermission"
+//	@Param			id	path		int	true	"MPermission id"
+//	@Success		200	{object}	response.Response
+//	@Failure		400	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_permission/{id} [put]
+func MPermissionUpdate(c *gin.Context) {
+	MPermissionResource.Update(c)
+}
+
+// MPermissionIndex godoc
+//
+//	@Summary		MPermissionIndex
+//	@Description	Get MPermission by id
+//	@Tags			mPermission
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			id	path		int	true	"MPermission id"
+//	@Param			_includeDeleted	query		bool	false	"include soft-deleted data, needs deleted:read"
+//	@Param			_onlyDeleted	query		bool	false	"only soft-deleted data, needs deleted:read"
+//	@Success		200	{object}	response.Response
+//	@Failure		400	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_permission/{id} [get]
+func MPermissionIndex(c *gin.Context) {
+	MPermissionResource.Index(c)
+}
+
+// MPermissionDelete godoc
+//
+//	@Summary		MPermissionDelete
+//	@Description	Delete MPermission by id
+//	@Tags			mPermission
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			id	path		int	true	"MPermission id"
+//	@Success		200	{object}	response.Response
+//	@Failure		400	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_permission/{id} [delete]
+func MPermissionDelete(c *gin.Context) {
+	MPermissionResource.Delete(c)
+}
+
+// MPermissionSoftDelete godoc
+//
+//	@Summary		MPermissionSoftDelete
+//	@Description	Soft Delete MPermission by id
+//	@Tags			mPermission
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			id	path		int	true	"MPermission id"
+//	@Success		200	{object}	response.Response
+//	@Failure		400	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_permission/delete/{id} [put]
+func MPermissionSoftDelete(c *gin.Context) {
+	MPermissionResource.SoftDelete(c)
+}
+
+// MPermissionRestore godoc
+//
+//	@Summary		MPermissionRestore
+//	@Description	Restore MPermission by id
+//	@Tags			mPermission
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			id	path		int	true	"MPermission id"
+//	@Success		200	{object}	response.Response
+//	@Failure		400	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_permission/restore/{id} [put]
+func MPermissionRestore(c *gin.Context) {
+	MPermissionResource.Restore(c)
+}
+
+// MPermissionHeader godoc
+//
+//	@Summary		MPermissionHeader
+//	@Description	Get MPermission header
+//	@Tags			mPermission
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Success		200	{object}	response.Response
+//	@Failure		400	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_permission/header [get]
+func MPermissionHeader(c *gin.Context) {
+	MPermissionResource.Header(c)
+}
@@ -1,12 +1,7 @@
 package controller
 
 import (
-	"encoding/json"
 	"errors"
-	"net/http"
-	"regexp"
-	"strconv"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -15,11 +10,17 @@ import (
 	"github.com/amsatrio/gin_notes/initializer"
 	"github.com/amsatrio/gin_notes/model"
 	"github.com/amsatrio/gin_notes/model/request"
-	"github.com/amsatrio/gin_notes/model/response"
 	"github.com/amsatrio/gin_notes/service"
 	"github.com/amsatrio/gin_notes/util"
 )
 
+// MRoleResource serves m_role, the grants of a role are set by
+// MRoleSetPermissions.
+var MRoleResource = NewResource(ResourceOptions[model.MRole]{
+	Service:    service.NewMRoleCrudService,
+	Permission: "roles",
+})
+
 // MRolePage godoc
 //
 //	@Summary		MRolePage
@@ -31,102 +32,27 @@ import (
 //	@Param			_page	query		string	false	"page" default(0)
 //	@Param			_size	query		string	false	"size" default(5)
 //	@Param			_sort	query		string	false	"sort"
-//	@Param			_filter	query		string	false	"filter"
+//	@Param			_filter	query		string	false	"filter, a JSON list of {id, value, matchMode, dataType, mode} or a group {mode, filters}. mode (AND or OR) joins a condition with the ones before it, on a group it joins the filters of the group"
 //	@Param			_q	query		string	false	"global filter"
+//	@Param			_cursor	query		bool	false	"keyset pagination"
+//	@Param			_after	query		string	false	"cursor of the next page"
+//	@Param			_before	query		string	false	"cursor of the previous page"
+//	@Param			_count	query		string	false	"EXACT, ESTIMATE or NONE" default(EXACT)
+//	@Param			_includeDeleted	query		bool	false	"include soft-deleted data, needs deleted:read"
+//	@Param			_onlyDeleted	query		bool	false	"only soft-deleted data, needs deleted:read"
 //	@Success		200	{object}	response.Response
 //	@Failure		400	{object}	response.Response
 //	@Failure		404	{object}	response.Response
 //	@Failure		500	{object}	response.Response
 //	@Router			/v1/m_role [get]
 func MRolePage(c *gin.Context) {
-	sortRequest := c.DefaultQuery("_sort", "[]")
-	pageRequest := c.DefaultQuery("_page", "0")
-	sizeRequest := c.DefaultQuery("_size", "10")
-	filterRequest := c.DefaultQuery("_filter", "[]")
-	searchRequest := c.DefaultQuery("_q", "")
-
-	pageInt, errorPageInt := strconv.Atoi(pageRequest)
-	sizeInt64, errorLimitInt64 := strconv.ParseInt(sizeRequest, 10, 64)
-	sizeInt, errorLimitInt := strconv.Atoi(sizeRequest)
-
-	if errorPageInt != nil {
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, errorPageInt.Error())
-		c.Abort()
-		return
-	}
-	if errorLimitInt64 != nil {
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, errorLimitInt64.Error())
-		c.Abort()
-		return
-	}
-	if errorLimitInt != nil {
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, errorLimitInt.Error())
-		c.Abort()
-		return
-	}
-
-	isLetterNumber := regexp.MustCompile(`^[a-zA-Z0-9\s]+$`).MatchString
-	if !isLetterNumber(searchRequest) && searchRequest != "" {
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, errors.New("global search must not contains special character"))
-		c.Abort()
-		return
-	}
-
-	var sorts []request.Sort
-	jsonUnmarshalErr := json.Unmarshal([]byte(sortRequest), &sorts)
-	if jsonUnmarshalErr != nil {
-		util.Log("ERROR", "controllers", "MRolePage", "jsonUnmarshalErr error: "+jsonUnmarshalErr.Error())
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, jsonUnmarshalErr)
-		c.Abort()
-		return
-	}
-	var filters []request.Filter
-	jsonUnmarshalErr = json.Unmarshal([]byte(filterRequest), &filters)
-	if jsonUnmarshalErr != nil {
-		util.Log("ERROR", "controllers", "MRolePage", "jsonUnmarshalErr error: "+jsonUnmarshalErr.Error())
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, jsonUnmarshalErr)
-		c.Abort()
-		return
-	}
-
-	mRoleService := service.NewMRoleServiceImpl(initializer.DB)
-	result, err := mRoleService.GetPageMRole(
-		c,
-		sorts,
-		filters,
-		searchRequest,
-		pageInt,
-		sizeInt64,
-		sizeInt)
-
-	if err != nil {
-		util.Log("ERROR", "controllers", "MRolePage", "error: "+err.Error())
-		c.Set(constant.ERROR_KEY, constant.ErrorRetrieveDataFailed)
-		c.Set(constant.ERROR_MESSAGE, err)
-		c.Abort()
-		return
-	}
-
-	res := &response.Response{}
-	res.Timestamp = response.JSONTime{Time: time.Now()}
-	res.Data = *result
-	res.Status = http.StatusOK
-	res.Message = "success"
-	res.Path = c.FullPath()
-
-	c.JSON(res.Status, res)
+	MRoleResource.Page(c)
 }
 
 // MRoleCreate godoc
 //
 //	@Summary		MRoleCreate
-//	@Description	Create MRole
+//	@Description	Create MRole, granted notes:read and notes:write
 //	@Tags			mRole
 //	@Accept			json
 //	@Produce		json
@@ -138,60 +64,7 @@ func MRolePage(c *gin.Context) {
 //	@Failure		500	{object}	response.Response
 //	@Router			/v1/m_role [post]
 func MRoleCreate(c *gin.Context) {
-
-	// get request body
-	body := model.MRole{}
-
-	// validate
-	err := c.ShouldBindJSON(&body)
-	if err != nil {
-		util.LogError("controllers", "MRoleCreate", "bind error: "+err.Error(), err)
-		out, _ := util.ValidateError(err)
-		if out != nil {
-			c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-			c.Set(constant.ERROR_MESSAGE, out)
-			c.Abort()
-			return
-		}
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, err)
-		c.Abort()
-		return
-	}
-
-	email := c.GetString("username")
-
-	// find mUser
-	mUserService := service.NewMUserServiceImpl(initializer.DB)
-	mUser, err := mUserService.GetMUserByEmail(c, email)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		util.Log("ERROR", "controllers", "MRoleCreate", "create error: "+err.Error())
-		c.Set(constant.ERROR_KEY, constant.ErrorUserNotFound)
-		c.Set(constant.ERROR_MESSAGE, err.Error())
-		c.Abort()
-		return
-	}
-
-	mRoleService := service.NewMRoleServiceImpl(initializer.DB)
-
-	err = mRoleService.CreateMRole(c, &body, mUser)
-
-	if err != nil {
-		util.Log("ERROR", "controllers", "MRoleCreate", "create error: "+err.Error())
-		c.Set(constant.ERROR_KEY, constant.ErrorSaveDataFailed)
-		c.Set(constant.ERROR_MESSAGE, err.Error())
-		c.Abort()
-		return
-	}
-
-	res := &response.Response{}
-	res.Timestamp = response.JSONTime{Time: time.Now()}
-	res.Data = body
-	res.Status = http.StatusOK
-	res.Message = "success"
-	res.Path = c.FullPath()
-
-	c.JSON(res.Status, res)
+	MRoleResource.Create(c)
 }
 
 // MRoleUpdate godoc
@@ -210,72 +83,7 @@ func MRoleCreate(c *gin.Context) {
 //	@Failure		500	{object}	response.Response
 //	@Router			/v1/m_role/{id} [put]
 func MRoleUpdate(c *gin.Context) {
-
-	id := c.Param("id")
-	var idUint uint
-	idUint64, err := strconv.ParseUint(id, 10, 32)
-	if err != nil {
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, err.Error())
-		c.Abort()
-		return
-	}
-	idUint = uint(idUint64)
-
-	body := model.MRole{}
-
-	// validate
-	err = c.ShouldBindJSON(&body)
-	if err != nil {
-		util.LogError("controllers", "MRoleUpdate", "bind error: "+err.Error(), err)
-		out, _ := util.ValidateError(err)
-		if out != nil {
-			c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-			c.Set(constant.ERROR_MESSAGE, out)
-			c.Abort()
-			return
-		}
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, err)
-		c.Abort()
-		return
-	}
-
-	body.Id = idUint
-
-	email := c.GetString("username")
-
-	// find mUser
-	mUserService := service.NewMUserServiceImpl(initializer.DB)
-	mUser, err := mUserService.GetMUserByEmail(c, email)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		util.Log("ERROR", "controllers", "MRoleUpdate", "error: "+err.Error())
-		c.Set(constant.ERROR_KEY, constant.ErrorUserNotFound)
-		c.Set(constant.ERROR_MESSAGE, err.Error())
-		c.Abort()
-		return
-	}
-
-	mRoleService := service.NewMRoleServiceImpl(initializer.DB)
-
-	err = mRoleService.UpdateMRole(c, &body, mUser)
-
-	if err != nil {
-		util.Log("ERROR", "controllers", "MRoleUpdate UpdateMRole", err.Error())
-		c.Set(constant.ERROR_KEY, constant.ErrorSaveDataFailed)
-		c.Set(constant.ERROR_MESSAGE, err)
-		c.Abort()
-		return
-	}
-
-	res := &response.Response{}
-	res.Timestamp = response.JSONTime{Time: time.Now()}
-	res.Data = body
-	res.Status = http.StatusOK
-	res.Message = "success"
-	res.Path = c.FullPath()
-
-	c.JSON(res.Status, res)
+	MRoleResource.Update(c)
 }
 
 // MRoleIndex godoc
@@ -287,50 +95,15 @@ func MRoleUpdate(c *gin.Context) {
 //	@Produce		json
 //	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
 //	@Param			id	path		int	true	"MRole id"
+//	@Param			_includeDeleted	query		bool	false	"include soft-deleted data, needs deleted:read"
+//	@Param			_onlyDeleted	query		bool	false	"only soft-deleted data, needs deleted:read"
 //	@Success		200	{object}	response.Response
 //	@Failure		400	{object}	response.Response
 //	@Failure		404	{object}	response.Response
 //	@Failure		500	{object}	response.Response
 //	@Router			/v1/m_role/{id} [get]
 func MRoleIndex(c *gin.Context) {
-
-	id := c.Param("id")
-	var idUint uint
-	idUint64, err := strconv.ParseUint(id, 10, 32)
-	if err != nil {
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, err.Error())
-		c.Abort()
-		return
-	}
-	idUint = uint(idUint64)
-
-	mRoleService := service.NewMRoleServiceImpl(initializer.DB)
-
-	mRole, err := mRoleService.GetMRole(c, idUint)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		c.Set(constant.ERROR_KEY, constant.ErrorDataNotFound)
-		c.Set(constant.ERROR_MESSAGE, err)
-		c.Abort()
-		return
-	}
-
-	if err != nil {
-		util.Log("ERROR", "controllers", "MRoleIndex", err.Error())
-		c.Set(constant.ERROR_KEY, constant.ErrorRetrieveDataFailed)
-		c.Set(constant.ERROR_MESSAGE, err)
-		c.Abort()
-		return
-	}
-
-	res := &response.Response{}
-	res.Timestamp = response.JSONTime{Time: time.Now()}
-	res.Data = mRole
-	res.Status = http.StatusOK
-	res.Message = "success"
-	res.Path = c.FullPath()
-
-	c.JSON(res.Status, res)
+	MRoleResource.Index(c)
 }
 
 // MRoleDelete godoc
@@ -348,140 +121,203 @@ func MRoleIndex(c *gin.Context) {
 //	@Failure		500	{object}	response.Response
 //	@Router			/v1/m_role/{id} [delete]
 func MRoleDelete(c *gin.Context) {
-	// get id from request param
-	idParam := c.Param("id")
-	var idUint uint
-	idUint64, err := strconv.ParseUint(idParam, 10, 32)
-	if err != nil {
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, err.Error())
-		c.Abort()
+	MRoleResource.Delete(c)
+}
+
+// MRoleSoftDelete godoc
+//
+//	@Summary		MRoleSoftDelete
+//	@Description	Soft Delete MRole by id
+//	@Tags			mRole
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			id	path		int	true	"MRole id"
+//	@Success		200	{object}	response.Response
+//	@Failure		400	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_role/delete/{id} [put]
+func MRoleSoftDelete(c *gin.Context) {
+	MRoleResource.SoftDelete(c)
+}
+
+// MRoleRestore godoc
+//
+//	@Summary		MRoleRestore
+//	@Description	Restore MRole by id
+//	@Tags			mRole
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			id	path		int	true	"MRole id"
+//	@Success		200	{object}	response.Response
+//	@Failure		400	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_role/restore/{id} [put]
+func MRoleRestore(c *gin.Context) {
+	MRoleResource.Restore(c)
+}
+
+// MRoleHeader godoc
+//
+//	@Summary		MRoleHeader
+//	@Description	Get MRole header
+//	@Tags			mRole
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Success		200	{object}	response.Response
+//	@Failure		400	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_role/header [get]
+func MRoleHeader(c *gin.Context) {
+	MRoleResource.Header(c)
+}
+
+// MRolePermissions godoc
+//
+//	@Summary		MRolePermissions
+//	@Description	Get the permissions of MRole by id
+//	@Tags			mRole
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			id	path		int	true	"MRole id"
+//	@Success		200	{object}	response.Response
+//	@Failure		400	{object}	response.Response
+//	@Failure		403	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_role/permissions/{id} [get]
+func MRolePermissions(c *gin.Context) {
+	idUint, ok := MRoleResource.paramId(c)
+	if !ok {
 		return
 	}
-	idUint = uint(idUint64)
-
-	email := c.GetString("username")
 
-	// find mUser
-	mUserService := service.NewMUserServiceImpl(initializer.DB)
-	mUser, err := mUserService.GetMUserByEmail(c, email)
+	mRoleService := service.NewMRoleServiceImpl(initializer.DB)
+	permissions, err := mRoleService.GetMRolePermissions(c, idUint)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		util.Log("ERROR", "controllers", "MRoleDelete", "create error: "+err.Error())
-		c.Set(constant.ERROR_KEY, constant.ErrorUserNotFound)
+		c.Set(constant.ERROR_KEY, constant.ErrorDataNotFound)
 		c.Set(constant.ERROR_MESSAGE, err.Error())
 		c.Abort()
 		return
 	}
-
-	mRoleService := service.NewMRoleServiceImpl(initializer.DB)
-
-	// delete mRole
-	err = mRoleService.DeleteMRole(c, idUint, mUser)
-
 	if err != nil {
-		c.Set(constant.ERROR_KEY, constant.ErrorDataNotFound)
+		c.Set(constant.ERROR_KEY, constant.ErrorRetrieveDataFailed)
 		c.Set(constant.ERROR_MESSAGE, err.Error())
 		c.Abort()
 		return
 	}
 
-	// return response
-	res := &response.Response{}
-	res.Timestamp = response.JSONTime{Time: time.Now()}
-	res.Data = nil
-	res.Status = http.StatusOK
-	res.Message = "success"
-	res.Path = c.FullPath()
-
-	c.JSON(res.Status, res)
+	MRoleResource.success(c, permissions)
 }
 
-// MRoleSoftDelete godoc
+// MRoleSetPermissions godoc
 //
-//	@Summary		MRoleSoftDelete
-//	@Description	Soft Delete MRole by id
+//	@Summary		MRoleSetPermissions
+//	@Description	Replace the permissions of MRole by id, users get them on their next login or refresh
 //	@Tags			mRole
 //	@Accept			json
 //	@Produce		json
 //	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
 //	@Param			id	path		int	true	"MRole id"
+//	@Param			permissions	body		request.RequestRolePermission	true	"Permission ids"
 //	@Success		200	{object}	response.Response
 //	@Failure		400	{object}	response.Response
+//	@Failure		403	{object}	response.Response
 //	@Failure		404	{object}	response.Response
 //	@Failure		500	{object}	response.Response
-//	@Router			/v1/m_role/delete/{id} [put]
-func MRoleSoftDelete(c *gin.Context) {
-	// get id from request param
-	id := c.Param("id")
-	var idUint uint
-	idUint64, err := strconv.ParseUint(id, 10, 32)
+//	@Router			/v1/m_role/permissions/{id} [put]
+func MRoleSetPermissions(c *gin.Context) {
+	idUint, ok := MRoleResource.paramId(c)
+	if !ok {
+		return
+	}
+
+	body := request.RequestRolePermission{}
+	err := c.ShouldBindBodyWithJSON(&body)
 	if err != nil {
+		out, _ := util.ValidateError(err)
+		if out != nil {
+			c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+			c.Set(constant.ERROR_MESSAGE, out)
+			c.Abort()
+			return
+		}
 		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, err)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
 		c.Abort()
 		return
 	}
-	idUint = uint(idUint64)
 
-	email := c.GetString("username")
+	mUserAccess, ok := MRoleResource.accessUser(c, "SetPermissions")
+	if !ok {
+		return
+	}
 
-	mUserService := service.NewMUserServiceImpl(initializer.DB)
-	mUser, err := mUserService.GetMUserByEmail(c, email)
+	mRoleService := service.NewMRoleServiceImpl(initializer.DB)
+	err = mRoleService.SetMRolePermissions(c, idUint, body.PermissionIds, mUserAccess)
+	if out := util.ValidateFieldError(err); out != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, out)
+		c.Abort()
+		return
+	}
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		util.Log("ERROR", "controllers", "MRoleSoftDelete", "create error: "+err.Error())
-		c.Set(constant.ERROR_KEY, constant.ErrorUserNotFound)
+		c.Set(constant.ERROR_KEY, constant.ErrorDataNotFound)
 		c.Set(constant.ERROR_MESSAGE, err.Error())
 		c.Abort()
 		return
 	}
-
-	mRoleService := service.NewMRoleServiceImpl(initializer.DB)
-
-	// delete mRole
-	err = mRoleService.SoftDeleteMRole(c, idUint, mUser)
-
-	// validate error
 	if err != nil {
-		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
-		c.Set(constant.ERROR_MESSAGE, err)
+		c.Set(constant.ERROR_KEY, constant.ErrorSaveDataFailed)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
 		c.Abort()
 		return
 	}
 
-	// return response
-	res := &response.Response{}
-	res.Timestamp = response.JSONTime{Time: time.Now()}
-	res.Data = nil
-	res.Status = http.StatusOK
-	res.Message = "success"
-	res.Path = c.FullPath()
-
-	c.JSON(res.Status, res)
+	MRoleResource.success(c, nil)
 }
 
-// MRoleHeader godoc
+// MRoleAuthorities godoc
 //
-//	@Summary		MRoleHeader
-//	@Description	Get MRole header
+//	@Summary		MRoleAuthorities
+//	@Description	Preview the authorities and the permissions a user of MRole by id gets, the ones inherited through AUTH_ROLE_HIERARCHY included
 //	@Tags			mRole
 //	@Accept			json
 //	@Produce		json
 //	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			id	path		int	true	"MRole id"
 //	@Success		200	{object}	response.Response
 //	@Failure		400	{object}	response.Response
+//	@Failure		403	{object}	response.Response
 //	@Failure		404	{object}	response.Response
 //	@Failure		500	{object}	response.Response
-//	@Router			/v1/m_role/header [get]
-func MRoleHeader(c *gin.Context) {
-	header := util.GetJSONFieldTypes(model.MRole{})
+//	@Router			/v1/m_role/authorities/{id} [get]
+func MRoleAuthorities(c *gin.Context) {
+	idUint, ok := MRoleResource.paramId(c)
+	if !ok {
+		return
+	}
 
-	// return response
-	res := &response.Response{}
-	res.Timestamp = response.JSONTime{Time: time.Now()}
-	res.Data = header
-	res.Status = http.StatusOK
-	res.Message = "success"
-	res.Path = c.FullPath()
+	mRoleService := service.NewMRoleServiceImpl(initializer.DB)
+	authorities, err := mRoleService.GetMRoleAuthorities(c, idUint)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Set(constant.ERROR_KEY, constant.ErrorDataNotFound)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
+	if err != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRetrieveDataFailed)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
 
-	c.JSON(res.Status, res)
+	MRoleResource.success(c, authorities)
 }
@@ -0,0 +1,675 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/amsatrio/gin_notes/constant"
+	"github.com/amsatrio/gin_notes/initializer"
+	"github.com/amsatrio/gin_notes/middleware"
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/model/request"
+	"github.com/amsatrio/gin_notes/model/response"
+	"github.com/amsatrio/gin_notes/service"
+	"github.com/amsatrio/gin_notes/util"
+)
+
+type Operation string
+
+const (
+	OP_PAGE        Operation = "PAGE"
+	OP_CREATE      Operation = "CREATE"
+	OP_UPDATE      Operation = "UPDATE"
+	OP_INDEX       Operation = "INDEX"
+	OP_SOFT_DELETE Operation = "SOFT_DELETE"
+	OP_DELETE      Operation = "DELETE"
+	OP_RESTORE     Operation = "RESTORE"
+	OP_HEADER      Operation = "HEADER"
+)
+
+const (
+	VIEW_MINE   = "mine"
+	VIEW_SHARED = "shared"
+)
+
+// PERMISSION_DELETED_READ grants the _includeDeleted and _onlyDeleted
+// switches of every resource.
+const PERMISSION_DELETED_READ = "deleted:read"
+
+// ResourceOptions configures the routes registered by RegisterResource.
+type ResourceOptions[T any] struct {
+	// Service builds the CrudService of the resource for every request.
+	Service func(db *gorm.DB) service.CrudService[T]
+
+	// Disable lists the operations that are not registered.
+	Disable []Operation
+
+	// Middleware runs before every handler of the resource.
+	Middleware []gin.HandlerFunc
+
+	// Permission prefixes the permissions the routes require: Page, Index and
+	// Header need Permission+":read", the other operations
+	// Permission+":write". Empty requires none.
+	Permission string
+
+	// Owned restricts every operation to the rows of the user of the request,
+	// see CrudConfig.OwnerField. A role granted Permission+":all_owners"
+	// reaches the rows of every user with _allOwners=true.
+	Owned bool
+}
+
+// Resource serves the Page/Create/Update/Index/Delete/SoftDelete/Restore/Header
+// endpoints of one model.
+type Resource[T any] struct {
+	name    string
+	options ResourceOptions[T]
+}
+
+// purger is implemented by every Resource, PurgeDeleted calls it for the
+// registered ones.
+type purger interface {
+	purge(context context.Context, deletedBefore time.Time) error
+}
+
+var registered []purger
+
+func NewResource[T any](options ResourceOptions[T]) *Resource[T] {
+	return &Resource[T]{
+		name:    reflect.TypeOf((*T)(nil)).Elem().Name(),
+		options: options,
+	}
+}
+
+// RegisterResource registers the CRUD routes of r under group/path. The
+// package level resource of a model, e.g. MNotesResource, is the one to
+// register: the handlers wrapping it carry the swagger docs of these routes.
+//
+//	POST   /path              Create
+//	GET    /path              Page
+//	PUT    /path/:id          Update
+//	GET    /path/:id          Index
+//	PUT    /path/delete/:id   SoftDelete
+//	PUT    /path/restore/:id  Restore
+//	DELETE /path/:id          Delete
+//	GET    /path/header       Header
+func RegisterResource[T any](group *gin.RouterGroup, path string, r *Resource[T]) {
+	path = "/" + strings.Trim(path, "/")
+
+	routes := []struct {
+		operation Operation
+		method    string
+		path      string
+		handler   gin.HandlerFunc
+	}{
+		{OP_CREATE, http.MethodPost, path, r.Create},
+		{OP_PAGE, http.MethodGet, path, r.Page},
+		{OP_UPDATE, http.MethodPut, path + "/:id", r.Update},
+		{OP_INDEX, http.MethodGet, path + "/:id", r.Index},
+		{OP_SOFT_DELETE, http.MethodPut, path + "/delete/:id", r.SoftDelete},
+		{OP_RESTORE, http.MethodPut, path + "/restore/:id", r.Restore},
+		{OP_DELETE, http.MethodDelete, path + "/:id", r.Delete},
+		{OP_HEADER, http.MethodGet, path + "/header", r.Header},
+	}
+
+	for _, route := range routes {
+		if r.disabled(route.operation) {
+			continue
+		}
+		handlers := append([]gin.HandlerFunc{}, r.options.Middleware...)
+		if permission := r.permission(route.operation); permission != "" {
+			handlers = append(handlers, middleware.RequirePermission(permission))
+		}
+		group.Handle(route.method, route.path, append(handlers, route.handler)...)
+	}
+
+	// a resource registered on several routers is purged once
+	for _, p := range registered {
+		if p == purger(r) {
+			return
+		}
+	}
+	registered = append(registered, r)
+}
+
+// PurgeDeleted hard-deletes the rows of every registered resource that were
+// soft-deleted before deletedBefore.
+func PurgeDeleted(context context.Context, deletedBefore time.Time) error {
+	var errs []error
+	for _, r := range registered {
+		if err := r.purge(context, deletedBefore); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Resource[T]) purge(context context.Context, deletedBefore time.Time) error {
+	if r.disabled(OP_DELETE) {
+		return nil
+	}
+	_, err := r.service().Purge(context, deletedBefore)
+	if err != nil {
+		util.Log("ERROR", "controllers", r.name+"Purge", "error: "+err.Error())
+		return errors.New(r.name + ": " + err.Error())
+	}
+	return nil
+}
+
+// permission returns the permission operation requires, empty for none.
+func (r *Resource[T]) permission(operation Operation) string {
+	if r.options.Permission == "" {
+		return ""
+	}
+	switch operation {
+	case OP_PAGE, OP_INDEX, OP_HEADER:
+		return r.options.Permission + ":read"
+	}
+	return r.options.Permission + ":write"
+}
+
+func (r *Resource[T]) disabled(operation Operation) bool {
+	for _, disabled := range r.options.Disable {
+		if disabled == operation {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Resource[T]) service() service.CrudService[T] {
+	return r.options.Service(initializer.DB)
+}
+
+// ownedService returns the service of the request, restricted to the rows of
+// its user when the resource is Owned. The _view switch picks the rows the
+// page lists: "mine" (default) or "shared" with the user. mUser is the user
+// of the request when already found. A request without a user, when
+// AUTH_JWT_ENABLE is not true, reaches every row.
+func (r *Resource[T]) ownedService(c *gin.Context, mUser *model.MUser, operation string) (service.CrudService[T], bool) {
+	crud := r.service()
+	if !r.options.Owned {
+		return crud, true
+	}
+
+	view := c.DefaultQuery("_view", VIEW_MINE)
+	if view != VIEW_MINE && view != VIEW_SHARED {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, "_view should be "+VIEW_MINE+" or "+VIEW_SHARED)
+		c.Abort()
+		return nil, false
+	}
+
+	if c.Query("_allOwners") == "true" {
+		if r.options.Permission == "" || !middleware.HasPermission(c, r.options.Permission+":all_owners") {
+			c.Set(constant.ERROR_KEY, constant.ErrorPermissionDenied)
+			c.Set(constant.ERROR_MESSAGE, "permission "+r.options.Permission+":all_owners is required to reach the data of every user")
+			c.Abort()
+			return nil, false
+		}
+		return crud, true
+	}
+	if c.GetString("username") == "" {
+		return crud, true
+	}
+
+	if mUser == nil {
+		var ok bool
+		mUser, ok = r.accessUser(c, operation)
+		if !ok {
+			return nil, false
+		}
+	}
+	if mUser == nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorUserNotFound)
+		c.Abort()
+		return nil, false
+	}
+	if view == VIEW_SHARED {
+		return crud.SharedWith(mUser), true
+	}
+	return crud.Owned(mUser), true
+}
+
+// denied aborts the request with ErrorPermissionDenied when err is one.
+func (r *Resource[T]) denied(c *gin.Context, err error) bool {
+	if !errors.Is(err, constant.ErrorPermissionDenied) {
+		return false
+	}
+	c.Set(constant.ERROR_KEY, constant.ErrorPermissionDenied)
+	c.Set(constant.ERROR_MESSAGE, "the data belongs to another user and is not shared enough with you")
+	c.Abort()
+	return true
+}
+
+func (r *Resource[T]) Page(c *gin.Context) {
+	sortRequest := c.DefaultQuery("_sort", "[]")
+	pageRequest := c.DefaultQuery("_page", "0")
+	sizeRequest := c.DefaultQuery("_size", "10")
+	filterRequest := c.DefaultQuery("_filter", "[]")
+	searchRequest := c.DefaultQuery("_q", "")
+	cursorRequest := request.Cursor{
+		Enabled: c.Query("_cursor") == "true" || c.Query("_after") != "" || c.Query("_before") != "",
+		After:   c.Query("_after"),
+		Before:  c.Query("_before"),
+		Count:   request.CountMode(strings.ToUpper(c.DefaultQuery("_count", request.COUNT_EXACT.String()))),
+	}
+
+	deletedRequest, ok := r.deletedMode(c)
+	if !ok {
+		return
+	}
+
+	pageInt, errorPageInt := strconv.Atoi(pageRequest)
+	sizeInt, errorLimitInt := strconv.Atoi(sizeRequest)
+
+	if errorPageInt != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, errorPageInt.Error())
+		c.Abort()
+		return
+	}
+	if errorLimitInt != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, errorLimitInt.Error())
+		c.Abort()
+		return
+	}
+
+	isLetterNumber := regexp.MustCompile(`^[a-zA-Z0-9\s]+$`).MatchString
+	if !isLetterNumber(searchRequest) && searchRequest != "" {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, errors.New("global search must not contains special character"))
+		c.Abort()
+		return
+	}
+
+	var sorts []request.Sort
+	jsonUnmarshalErr := json.Unmarshal([]byte(sortRequest), &sorts)
+	if jsonUnmarshalErr != nil {
+		util.Log("ERROR", "controllers", r.name+"Page", "jsonUnmarshalErr error: "+jsonUnmarshalErr.Error())
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, jsonUnmarshalErr)
+		c.Abort()
+		return
+	}
+	var filters request.Filters
+	jsonUnmarshalErr = json.Unmarshal([]byte(filterRequest), &filters)
+	if jsonUnmarshalErr != nil {
+		util.Log("ERROR", "controllers", r.name+"Page", "jsonUnmarshalErr error: "+jsonUnmarshalErr.Error())
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, jsonUnmarshalErr)
+		c.Abort()
+		return
+	}
+
+	crud, ok := r.ownedService(c, nil, "Page")
+	if !ok {
+		return
+	}
+
+	result, err := crud.GetPage(
+		c,
+		sorts,
+		filters,
+		searchRequest,
+		pageInt,
+		sizeInt,
+		cursorRequest,
+		deletedRequest)
+
+	if out := util.ValidateQueryError(err); out != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, out)
+		c.Abort()
+		return
+	}
+
+	if err != nil {
+		util.Log("ERROR", "controllers", r.name+"Page", "error: "+err.Error())
+		c.Set(constant.ERROR_KEY, constant.ErrorRetrieveDataFailed)
+		c.Set(constant.ERROR_MESSAGE, err)
+		c.Abort()
+		return
+	}
+
+	r.success(c, *result)
+}
+
+func (r *Resource[T]) Create(c *gin.Context) {
+
+	// get request body
+	var body T
+
+	// validate
+	if !r.bind(c, &body, "Create") {
+		return
+	}
+
+	mUser, ok := r.accessUser(c, "Create")
+	if !ok {
+		return
+	}
+
+	err := r.service().Create(c, &body, mUser)
+
+	if out := util.ValidateFieldError(err); out != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, out)
+		c.Abort()
+		return
+	}
+
+	if err != nil {
+		util.Log("ERROR", "controllers", r.name+"Create", "create error: "+err.Error())
+		c.Set(constant.ERROR_KEY, constant.ErrorSaveDataFailed)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
+
+	r.success(c, body)
+}
+
+func (r *Resource[T]) Update(c *gin.Context) {
+
+	idUint, ok := r.paramId(c)
+	if !ok {
+		return
+	}
+
+	var body T
+
+	// validate
+	if !r.bind(c, &body, "Update") {
+		return
+	}
+
+	if err := setId(&body, idUint); err != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
+
+	mUser, ok := r.accessUser(c, "Update")
+	if !ok {
+		return
+	}
+	crud, ok := r.ownedService(c, mUser, "Update")
+	if !ok {
+		return
+	}
+
+	err := crud.Update(c, &body, mUser)
+	if r.denied(c, err) {
+		return
+	}
+
+	if out := util.ValidateFieldError(err); out != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, out)
+		c.Abort()
+		return
+	}
+
+	if err != nil {
+		util.Log("ERROR", "controllers", r.name+"Update Update"+r.name, err.Error())
+		c.Set(constant.ERROR_KEY, constant.ErrorSaveDataFailed)
+		c.Set(constant.ERROR_MESSAGE, err)
+		c.Abort()
+		return
+	}
+
+	r.success(c, body)
+}
+
+func (r *Resource[T]) Index(c *gin.Context) {
+
+	idUint, ok := r.paramId(c)
+	if !ok {
+		return
+	}
+
+	deletedRequest, ok := r.deletedMode(c)
+	if !ok {
+		return
+	}
+
+	crud, ok := r.ownedService(c, nil, "Index")
+	if !ok {
+		return
+	}
+
+	data, err := crud.Find(c, idUint, deletedRequest)
+	if r.denied(c, err) {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Set(constant.ERROR_KEY, constant.ErrorDataNotFound)
+		c.Set(constant.ERROR_MESSAGE, err)
+		c.Abort()
+		return
+	}
+
+	if err != nil {
+		util.Log("ERROR", "controllers", r.name+"Index", err.Error())
+		c.Set(constant.ERROR_KEY, constant.ErrorRetrieveDataFailed)
+		c.Set(constant.ERROR_MESSAGE, err)
+		c.Abort()
+		return
+	}
+
+	r.success(c, data)
+}
+
+func (r *Resource[T]) Delete(c *gin.Context) {
+	// get id from request param
+	idUint, ok := r.paramId(c)
+	if !ok {
+		return
+	}
+
+	mUser, ok := r.accessUser(c, "Delete")
+	if !ok {
+		return
+	}
+	crud, ok := r.ownedService(c, mUser, "Delete")
+	if !ok {
+		return
+	}
+
+	err := crud.Delete(c, idUint, mUser)
+	if r.denied(c, err) {
+		return
+	}
+
+	if err != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorDataNotFound)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
+
+	// return response
+	r.success(c, nil)
+}
+
+func (r *Resource[T]) SoftDelete(c *gin.Context) {
+	// get id from request param
+	idUint, ok := r.paramId(c)
+	if !ok {
+		return
+	}
+
+	mUser, ok := r.accessUser(c, "SoftDelete")
+	if !ok {
+		return
+	}
+	crud, ok := r.ownedService(c, mUser, "SoftDelete")
+	if !ok {
+		return
+	}
+
+	err := crud.SoftDelete(c, idUint, mUser)
+	if r.denied(c, err) {
+		return
+	}
+
+	// validate error
+	if err != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, err)
+		c.Abort()
+		return
+	}
+
+	// return response
+	r.success(c, nil)
+}
+
+func (r *Resource[T]) Restore(c *gin.Context) {
+	// get id from request param
+	idUint, ok := r.paramId(c)
+	if !ok {
+		return
+	}
+
+	mUser, ok := r.accessUser(c, "Restore")
+	if !ok {
+		return
+	}
+	crud, ok := r.ownedService(c, mUser, "Restore")
+	if !ok {
+		return
+	}
+
+	err := crud.Restore(c, idUint, mUser)
+	if r.denied(c, err) {
+		return
+	}
+
+	// validate error
+	if err != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorDataNotFound)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
+
+	// return response
+	r.success(c, nil)
+}
+
+func (r *Resource[T]) Header(c *gin.Context) {
+	var data T
+	header := util.GetJSONFieldTypes(data)
+
+	// return response
+	r.success(c, header)
+}
+
+func (r *Resource[T]) success(c *gin.Context, data interface{}) {
+	res := &response.Response{}
+	res.Timestamp = response.JSONTime{Time: time.Now()}
+	res.Data = data
+	res.Status = http.StatusOK
+	res.Message = "success"
+	res.Path = c.FullPath()
+
+	c.JSON(res.Status, res)
+}
+
+func (r *Resource[T]) paramId(c *gin.Context) (uint, bool) {
+	idUint64, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return 0, false
+	}
+	return uint(idUint64), true
+}
+
+// deletedMode reads the _includeDeleted and _onlyDeleted switches, only a
+// role granted PERMISSION_DELETED_READ may see the soft-deleted rows.
+func (r *Resource[T]) deletedMode(c *gin.Context) (request.DeletedMode, bool) {
+	includeDeleted := c.Query("_includeDeleted") == "true"
+	onlyDeleted := c.Query("_onlyDeleted") == "true"
+
+	if includeDeleted && onlyDeleted {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, "_includeDeleted and _onlyDeleted can not be used together")
+		c.Abort()
+		return "", false
+	}
+	if !includeDeleted && !onlyDeleted {
+		return request.DELETED_EXCLUDE, true
+	}
+
+	if !middleware.HasPermission(c, PERMISSION_DELETED_READ) {
+		c.Set(constant.ERROR_KEY, constant.ErrorPermissionDenied)
+		c.Set(constant.ERROR_MESSAGE, "permission "+PERMISSION_DELETED_READ+" is required to read deleted data")
+		c.Abort()
+		return "", false
+	}
+	if onlyDeleted {
+		return request.DELETED_ONLY, true
+	}
+	return request.DELETED_INCLUDE, true
+}
+
+func (r *Resource[T]) bind(c *gin.Context, body *T, operation string) bool {
+	err := c.ShouldBindJSON(body)
+	if err == nil {
+		return true
+	}
+
+	util.LogError("controllers", r.name+operation, "bind error: "+err.Error(), err)
+	out, _ := util.ValidateError(err)
+	if out != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, out)
+		c.Abort()
+		return false
+	}
+	c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+	c.Set(constant.ERROR_MESSAGE, err)
+	c.Abort()
+	return false
+}
+
+// accessUser finds the user of the request for the audit fields.
+func (r *Resource[T]) accessUser(c *gin.Context, operation string) (*model.MUser, bool) {
+	email := c.GetString("username")
+
+	mUserService := service.NewMUserServiceImpl(initializer.DB)
+	mUser, err := mUserService.GetMUserByEmail(c, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		util.Log("ERROR", "controllers", r.name+operation, "error: "+err.Error())
+		c.Set(constant.ERROR_KEY, constant.ErrorUserNotFound)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return nil, false
+	}
+	return mUser, true
+}
+
+// setId sets the primary key of body to the id of the path.
+func setId[T any](body *T, id uint) error {
+	field := reflect.ValueOf(body).Elem().FieldByName("Id")
+	if !field.IsValid() || !field.CanSet() || field.Kind() != reflect.Uint {
+		return errors.New("resource has no id")
+	}
+	field.SetUint(uint64(id))
+	return nil
+}
@@ -0,0 +1,231 @@
+package controller
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/amsatrio/gin_notes/constant"
+	"github.com/amsatrio/gin_notes/initializer"
+	"github.com/amsatrio/gin_notes/middleware"
+	"github.com/amsatrio/gin_notes/service"
+	"github.com/amsatrio/gin_notes/util"
+)
+
+// MNotesRevisions godoc
+//
+//	@Summary		MNotesRevisions
+//	@Description	Get the revisions of MNotes by id, the latest first. NOTES_REVISION_RETENTION may keep only the last ones
+//	@Tags			mNotes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			id	path		int	true	"MNotes id"
+//	@Param			_allOwners	query		bool	false	"notes of every user, needs notes:all_owners"
+//	@Success		200	{object}	response.Response{data=[]model.TNoteRevision}
+//	@Failure		400	{object}	response.Response
+//	@Failure		403	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_notes/{id}/revisions [get]
+func MNotesRevisions(c *gin.Context) {
+	idUint, _, ok := authorizedMNotes(c, nil, "Revisions", service.ACCESS_READ)
+	if !ok {
+		return
+	}
+
+	tNoteRevisionService := service.NewTNoteRevisionServiceImpl(initializer.DB)
+	revisions, err := tNoteRevisionService.GetTNoteRevisions(c, idUint)
+	if err != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRetrieveDataFailed)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
+
+	MNotesResource.success(c, revisions)
+}
+
+// MNotesRevision godoc
+//
+//	@Summary		MNotesRevision
+//	@Description	Get a revision of MNotes by id
+//	@Tags			mNotes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			id	path		int	true	"MNotes id"
+//	@Param			revision	path		int	true	"revision number"
+//	@Param			_allOwners	query		bool	false	"notes of every user, needs notes:all_owners"
+//	@Success		200	{object}	response.Response{data=model.TNoteRevision}
+//	@Failure		400	{object}	response.Response
+//	@Failure		403	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_notes/{id}/revisions/{revision} [get]
+func MNotesRevision(c *gin.Context) {
+	revision, ok := revisionParam(c, c.Param("revision"))
+	if !ok {
+		return
+	}
+	idUint, _, ok := authorizedMNotes(c, nil, "Revision", service.ACCESS_READ)
+	if !ok {
+		return
+	}
+
+	tNoteRevisionService := service.NewTNoteRevisionServiceImpl(initializer.DB)
+	tNoteRevision, err := tNoteRevisionService.GetTNoteRevision(c, idUint, revision)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Set(constant.ERROR_KEY, constant.ErrorDataNotFound)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
+	if err != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRetrieveDataFailed)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
+
+	MNotesResource.success(c, tNoteRevision)
+}
+
+// MNotesRevisionDiff godoc
+//
+//	@Summary		MNotesRevisionDiff
+//	@Description	Get the line diff of the title and the content of MNotes by id between two revisions
+//	@Tags			mNotes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			id	path		int	true	"MNotes id"
+//	@Param			from	query		int	true	"old revision number"
+//	@Param			to	query		int	false	"new revision number, the latest by default"
+//	@Param			_allOwners	query		bool	false	"notes of every user, needs notes:all_owners"
+//	@Success		200	{object}	response.Response{data=response.ResponseNoteRevisionDiff}
+//	@Failure		400	{object}	response.Response
+//	@Failure		403	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_notes/{id}/diff [get]
+func MNotesRevisionDiff(c *gin.Context) {
+	from, ok := revisionParam(c, c.Query("from"))
+	if !ok {
+		return
+	}
+	var to uint
+	if c.Query("to") != "" {
+		to, ok = revisionParam(c, c.Query("to"))
+		if !ok {
+			return
+		}
+	}
+	idUint, _, ok := authorizedMNotes(c, nil, "RevisionDiff", service.ACCESS_READ)
+	if !ok {
+		return
+	}
+
+	tNoteRevisionService := service.NewTNoteRevisionServiceImpl(initializer.DB)
+	diff, err := tNoteRevisionService.DiffTNoteRevisions(c, idUint, from, to)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Set(constant.ERROR_KEY, constant.ErrorDataNotFound)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
+	if err != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRetrieveDataFailed)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
+
+	MNotesResource.success(c, diff)
+}
+
+// MNotesRestoreRevision godoc
+//
+//	@Summary		MNotesRestoreRevision
+//	@Description	Update MNotes by id to the title and the content of one of its revisions, saved as a new revision. Needs the edit permission on the note
+//	@Tags			mNotes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			id	path		int	true	"MNotes id"
+//	@Param			revision	path		int	true	"revision number"
+//	@Param			_allOwners	query		bool	false	"notes of every user, needs notes:all_owners"
+//	@Success		200	{object}	response.Response{data=model.MNotes}
+//	@Failure		400	{object}	response.Response
+//	@Failure		403	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_notes/{id}/revisions/{revision}/restore [post]
+func MNotesRestoreRevision(c *gin.Context) {
+	revision, ok := revisionParam(c, c.Param("revision"))
+	if !ok {
+		return
+	}
+	mUserAccess, ok := MNotesResource.accessUser(c, "RestoreRevision")
+	if !ok {
+		return
+	}
+	idUint, crud, ok := authorizedMNotes(c, mUserAccess, "RestoreRevision", service.ACCESS_UPDATE)
+	if !ok {
+		return
+	}
+
+	tNoteRevisionService := service.NewTNoteRevisionServiceImpl(initializer.DB)
+	mNotes, err := tNoteRevisionService.RestoreTNoteRevision(c, crud, idUint, revision, mUserAccess)
+	if MNotesResource.denied(c, err) {
+		return
+	}
+	if out := util.ValidateFieldError(err); out != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, out)
+		c.Abort()
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Set(constant.ERROR_KEY, constant.ErrorDataNotFound)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
+	if err != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorSaveDataFailed)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
+
+	evictRestoredMNotes(c)
+	MNotesResource.success(c, mNotes)
+}
+
+// evictRestoredMNotes drops the cached notes, the restored note is in every
+// one of them.
+func evictRestoredMNotes(c *gin.Context) {
+	prefix := strings.Split(c.FullPath(), "/:id/")[0]
+	if err := middleware.RedisEvict(prefix); err != nil {
+		util.Log("ERROR", "controllers", "MNotesRestoreRevision", "evict cache error: "+err.Error())
+	}
+}
+
+// revisionParam parses a revision number, which starts at 1.
+func revisionParam(c *gin.Context, value string) (uint, bool) {
+	revision, err := strconv.ParseUint(value, 10, 32)
+	if err == nil && revision == 0 {
+		err = errors.New("a revision starts at 1")
+	}
+	if err != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return 0, false
+	}
+	return uint(revision), true
+}
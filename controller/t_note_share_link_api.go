@@ -0,0 +1,261 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/amsatrio/gin_notes/constant"
+	"github.com/amsatrio/gin_notes/initializer"
+	"github.com/amsatrio/gin_notes/model/request"
+	"github.com/amsatrio/gin_notes/model/response"
+	"github.com/amsatrio/gin_notes/service"
+	"github.com/amsatrio/gin_notes/util"
+)
+
+// sharedNotePage renders a note opened by a share link, or the password form
+// of the link when Password is true.
+var sharedNotePage = template.Must(template.New("shared_note").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>{{if .Password}}Password required{{else}}{{.Note.Title}}{{end}}</title>
+</head>
+<body>
+{{if .Password}}
+<form method="post">
+<label>Password <input type="password" name="password" autofocus></label>
+<button type="submit">Open</button>
+{{if .Invalid}}<p>The password is invalid.</p>{{end}}
+</form>
+{{else}}
+<h1>{{.Note.Title}}</h1>
+<pre>{{.Note.Content}}</pre>
+{{end}}
+</body>
+</html>
+`))
+
+// MNotesShareLinks godoc
+//
+//	@Summary		MNotesShareLinks
+//	@Description	Get the public links of MNotes by id with their access counts, needs the owner permission on the note
+//	@Tags			mNotes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			id	path		int	true	"MNotes id"
+//	@Param			_allOwners	query		bool	false	"notes of every user, needs notes:all_owners"
+//	@Success		200	{object}	response.Response
+//	@Failure		400	{object}	response.Response
+//	@Failure		403	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_notes/{id}/share_link [get]
+func MNotesShareLinks(c *gin.Context) {
+	idUint, ok := sharedMNotes(c, nil, "ShareLinks")
+	if !ok {
+		return
+	}
+
+	tNoteShareLinkService := service.NewTNoteShareLinkServiceImpl(initializer.DB)
+	links, err := tNoteShareLinkService.GetTNoteShareLinks(c, idUint)
+	if err != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRetrieveDataFailed)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
+
+	MNotesResource.success(c, links)
+}
+
+// MNotesCreateShareLink godoc
+//
+//	@Summary		MNotesCreateShareLink
+//	@Description	Publish MNotes by id read-only at /p/{slug}, optionally until expiredOn and behind a password. The slug is only returned here. Needs the owner permission on the note
+//	@Tags			mNotes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			id	path		int	true	"MNotes id"
+//	@Param			link	body		request.RequestNoteShareLink	true	"Expiry and password, both optional"
+//	@Param			_allOwners	query		bool	false	"notes of every user, needs notes:all_owners"
+//	@Success		200	{object}	response.Response{data=response.ResponseNoteShareLink}
+//	@Failure		400	{object}	response.Response
+//	@Failure		403	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_notes/{id}/share_link [post]
+func MNotesCreateShareLink(c *gin.Context) {
+	body := request.RequestNoteShareLink{}
+	err := c.ShouldBindBodyWithJSON(&body)
+	if err != nil {
+		out, _ := util.ValidateError(err)
+		if out != nil {
+			c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+			c.Set(constant.ERROR_MESSAGE, out)
+			c.Abort()
+			return
+		}
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
+
+	mUserAccess, ok := MNotesResource.accessUser(c, "CreateShareLink")
+	if !ok {
+		return
+	}
+	idUint, ok := sharedMNotes(c, mUserAccess, "CreateShareLink")
+	if !ok {
+		return
+	}
+
+	tNoteShareLinkService := service.NewTNoteShareLinkServiceImpl(initializer.DB)
+	link, err := tNoteShareLinkService.CreateTNoteShareLink(c, idUint, body, mUserAccess)
+	if out := util.ValidateFieldError(err); out != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, out)
+		c.Abort()
+		return
+	}
+	if err != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorSaveDataFailed)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
+
+	MNotesResource.success(c, link)
+}
+
+// MNotesRevokeShareLink godoc
+//
+//	@Summary		MNotesRevokeShareLink
+//	@Description	Revoke a public link of MNotes by id, needs the owner permission on the note
+//	@Tags			mNotes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Accept-Encoding	header	string	false	"gzip" default(gzip)
+//	@Param			id	path		int	true	"MNotes id"
+//	@Param			linkId	path		int	true	"TNoteShareLink id"
+//	@Param			_allOwners	query		bool	false	"notes of every user, needs notes:all_owners"
+//	@Success		200	{object}	response.Response
+//	@Failure		400	{object}	response.Response
+//	@Failure		403	{object}	response.Response
+//	@Failure		404	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/v1/m_notes/{id}/share_link/{linkId} [delete]
+func MNotesRevokeShareLink(c *gin.Context) {
+	linkIdUint64, err := strconv.ParseUint(c.Param("linkId"), 10, 32)
+	if err != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorRequestInvalid)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
+
+	idUint, ok := sharedMNotes(c, nil, "RevokeShareLink")
+	if !ok {
+		return
+	}
+
+	tNoteShareLinkService := service.NewTNoteShareLinkServiceImpl(initializer.DB)
+	err = tNoteShareLinkService.RevokeTNoteShareLink(c, idUint, uint(linkIdUint64))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Set(constant.ERROR_KEY, constant.ErrorDataNotFound)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
+	if err != nil {
+		c.Set(constant.ERROR_KEY, constant.ErrorSaveDataFailed)
+		c.Set(constant.ERROR_MESSAGE, err.Error())
+		c.Abort()
+		return
+	}
+
+	MNotesResource.success(c, nil)
+}
+
+// NoteShareLinkOpen godoc
+//
+//	@Summary		NoteShareLinkOpen
+//	@Description	Read the note of a public link, as JSON or as HTML when the Accept header asks for it. The password of a protected link is sent in the X-Share-Password header, or as the password field of a form post
+//	@Tags			mNotes
+//	@Produce		json,html
+//	@Param			slug	path		string	true	"slug of the link"
+//	@Param			X-Share-Password	header	string	false	"password of a protected link"
+//	@Success		200	{object}	response.Response{data=response.ResponseSharedNote}
+//	@Failure		400	{object}	response.Response
+//	@Failure		401	{object}	response.Response
+//	@Failure		500	{object}	response.Response
+//	@Router			/p/{slug} [get]
+func NoteShareLinkOpen(c *gin.Context) {
+	// the note is not for search engines nor for the sites it links to
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Robots-Tag", "noindex")
+	c.Header("Referrer-Policy", "no-referrer")
+
+	password := c.GetHeader("X-Share-Password")
+	if c.Request.Method == http.MethodPost {
+		password = c.PostForm("password")
+	}
+	html := c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML
+
+	tNoteShareLinkService := service.NewTNoteShareLinkServiceImpl(initializer.DB)
+	mNotes, err := tNoteShareLinkService.OpenTNoteShareLink(c, c.Param("slug"), password)
+	if errors.Is(err, constant.ErrorSharePasswordInvalid) {
+		if html {
+			renderSharedNote(c, http.StatusUnauthorized, gin.H{"Password": true, "Invalid": password != ""})
+			return
+		}
+		c.Set(constant.ERROR_KEY, constant.ErrorSharePasswordInvalid)
+		c.Abort()
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Set(constant.ERROR_KEY, constant.ErrorDataNotFound)
+		c.Set(constant.ERROR_MESSAGE, "the link does not exist, expired or was revoked")
+		c.Abort()
+		return
+	}
+	if err != nil {
+		util.Log("ERROR", "controllers", "NoteShareLinkOpen", "error: "+err.Error())
+		c.Set(constant.ERROR_KEY, constant.ErrorRetrieveDataFailed)
+		c.Abort()
+		return
+	}
+
+	sharedNote := response.ResponseSharedNote{
+		Title:      mNotes.Title,
+		Content:    mNotes.Content,
+		CreatedOn:  mNotes.CreatedOn,
+		ModifiedOn: mNotes.ModifiedOn,
+	}
+	if html {
+		renderSharedNote(c, http.StatusOK, gin.H{"Note": sharedNote})
+		return
+	}
+	MNotesResource.success(c, sharedNote)
+}
+
+func renderSharedNote(c *gin.Context, status int, data gin.H) {
+	page := new(bytes.Buffer)
+	if err := sharedNotePage.Execute(page, data); err != nil {
+		util.Log("ERROR", "controllers", "NoteShareLinkOpen", "render error: "+err.Error())
+		c.Set(constant.ERROR_KEY, constant.ErrorRetrieveDataFailed)
+		c.Abort()
+		return
+	}
+	c.Header("Content-Security-Policy", "default-src 'none'; form-action 'self'")
+	c.Data(status, "text/html; charset=utf-8", page.Bytes())
+}
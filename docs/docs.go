@@ -24,6 +24,99 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Public keys of the tokens as a JSON Web Key Set",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "JwtJwks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/p/{slug}": {
+            "get": {
+                "description": "Read the note of a public link, as JSON or as HTML when the Accept header asks for it. The password of a protected link is sent in the X-Share-Password header, or as the password field of a form post",
+                "produces": [
+                    "application/json",
+                    "text/html"
+                ],
+                "tags": [
+                    "mNotes"
+                ],
+                "summary": "NoteShareLinkOpen",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "slug of the link",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "password of a protected link",
+                        "name": "X-Share-Password",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.ResponseSharedNote"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/m_biodata": {
             "get": {
                 "description": "Get Page MBiodata",
@@ -67,7 +160,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "filter",
+                        "description": "filter, a JSON list of {id, value, matchMode, dataType, mode} or a group {mode, filters}. mode (AND or OR) joins a condition with the ones before it, on a group it joins the filters of the group",
                         "name": "_filter",
                         "in": "query"
                     },
@@ -76,6 +169,43 @@ const docTemplate = `{
                         "description": "global filter",
                         "name": "_q",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "keyset pagination",
+                        "name": "_cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the next page",
+                        "name": "_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the previous page",
+                        "name": "_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "EXACT",
+                        "description": "EXACT, ESTIMATE or NONE",
+                        "name": "_count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include soft-deleted data, needs deleted:read",
+                        "name": "_includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only soft-deleted data, needs deleted:read",
+                        "name": "_onlyDeleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -270,6 +400,63 @@ const docTemplate = `{
                 }
             }
         },
+        "/v1/m_biodata/restore/{id}": {
+            "put": {
+                "description": "Restore MBiodata by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mBiodata"
+                ],
+                "summary": "MBiodataRestore",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MBiodata id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/m_biodata/{id}": {
             "get": {
                 "description": "Get MBiodata by id",
@@ -297,6 +484,18 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include soft-deleted data, needs deleted:read",
+                        "name": "_includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only soft-deleted data, needs deleted:read",
+                        "name": "_onlyDeleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -448,7 +647,7 @@ const docTemplate = `{
         },
         "/v1/m_notes": {
             "get": {
-                "description": "Get Page MNotes",
+                "description": "Get Page MNotes of the user, or the ones shared with the user",
                 "consumes": [
                     "application/json"
                 ],
@@ -489,7 +688,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "filter",
+                        "description": "filter, a JSON list of {id, value, matchMode, dataType, mode} or a group {mode, filters}. mode (AND or OR) joins a condition with the ones before it, on a group it joins the filters of the group",
                         "name": "_filter",
                         "in": "query"
                     },
@@ -498,6 +697,56 @@ const docTemplate = `{
                         "description": "global filter",
                         "name": "_q",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "keyset pagination",
+                        "name": "_cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the next page",
+                        "name": "_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the previous page",
+                        "name": "_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "EXACT",
+                        "description": "EXACT, ESTIMATE or NONE",
+                        "name": "_count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include soft-deleted data, needs deleted:read",
+                        "name": "_includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only soft-deleted data, needs deleted:read",
+                        "name": "_onlyDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "notes of every user, needs notes:all_owners",
+                        "name": "_allOwners",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "mine",
+                        "description": "mine or shared (with me)",
+                        "name": "_view",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -513,6 +762,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -612,6 +867,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "notes of every user, needs notes:all_owners",
+                        "name": "_allOwners",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -627,6 +888,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -692,9 +959,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/m_notes/{id}": {
-            "get": {
-                "description": "Get MNotes by id",
+        "/v1/m_notes/restore/{id}": {
+            "put": {
+                "description": "Restore MNotes by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -704,7 +971,7 @@ const docTemplate = `{
                 "tags": [
                     "mNotes"
                 ],
-                "summary": "MNotesIndex",
+                "summary": "MNotesRestore",
                 "parameters": [
                     {
                         "type": "string",
@@ -719,6 +986,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "notes of every user, needs notes:all_owners",
+                        "name": "_allOwners",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -734,6 +1007,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -747,9 +1026,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update MNotes",
+            }
+        },
+        "/v1/m_notes/{id}": {
+            "get": {
+                "description": "Get MNotes by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -759,7 +1040,7 @@ const docTemplate = `{
                 "tags": [
                     "mNotes"
                 ],
-                "summary": "MNotesUpdate",
+                "summary": "MNotesIndex",
                 "parameters": [
                     {
                         "type": "string",
@@ -768,21 +1049,30 @@ const docTemplate = `{
                         "name": "Accept-Encoding",
                         "in": "header"
                     },
-                    {
-                        "description": "Update MNotes",
-                        "name": "mNotes",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.MNotes"
-                        }
-                    },
                     {
                         "type": "integer",
                         "description": "MNotes id",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include soft-deleted data, needs deleted:read",
+                        "name": "_includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only soft-deleted data, needs deleted:read",
+                        "name": "_onlyDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "notes of every user, needs notes:all_owners",
+                        "name": "_allOwners",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -798,6 +1088,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -812,8 +1108,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Delete MNotes by id",
+            "put": {
+                "description": "Update MNotes",
                 "consumes": [
                     "application/json"
                 ],
@@ -823,7 +1119,7 @@ const docTemplate = `{
                 "tags": [
                     "mNotes"
                 ],
-                "summary": "MNotesDelete",
+                "summary": "MNotesUpdate",
                 "parameters": [
                     {
                         "type": "string",
@@ -832,12 +1128,27 @@ const docTemplate = `{
                         "name": "Accept-Encoding",
                         "in": "header"
                     },
+                    {
+                        "description": "Update MNotes",
+                        "name": "mNotes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.MNotes"
+                        }
+                    },
                     {
                         "type": "integer",
                         "description": "MNotes id",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "notes of every user, needs notes:all_owners",
+                        "name": "_allOwners",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -853,6 +1164,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -866,13 +1183,1762 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/m_role": {
-            "get": {
-                "description": "Get Page MRole",
-                "consumes": [
-                    "application/json"
+            },
+            "delete": {
+                "description": "Delete MNotes by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mNotes"
+                ],
+                "summary": "MNotesDelete",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MNotes id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "notes of every user, needs notes:all_owners",
+                        "name": "_allOwners",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/m_notes/{id}/diff": {
+            "get": {
+                "description": "Get the line diff of the title and the content of MNotes by id between two revisions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mNotes"
+                ],
+                "summary": "MNotesRevisionDiff",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MNotes id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "old revision number",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "new revision number, the latest by default",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "notes of every user, needs notes:all_owners",
+                        "name": "_allOwners",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.ResponseNoteRevisionDiff"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/m_notes/{id}/revisions": {
+            "get": {
+                "description": "Get the revisions of MNotes by id, the latest first. NOTES_REVISION_RETENTION may keep only the last ones",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mNotes"
+                ],
+                "summary": "MNotesRevisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MNotes id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "notes of every user, needs notes:all_owners",
+                        "name": "_allOwners",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.TNoteRevision"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/m_notes/{id}/revisions/{revision}": {
+            "get": {
+                "description": "Get a revision of MNotes by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mNotes"
+                ],
+                "summary": "MNotesRevision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MNotes id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "revision number",
+                        "name": "revision",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "notes of every user, needs notes:all_owners",
+                        "name": "_allOwners",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.TNoteRevision"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/m_notes/{id}/revisions/{revision}/restore": {
+            "post": {
+                "description": "Update MNotes by id to the title and the content of one of its revisions, saved as a new revision. Needs the edit permission on the note",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mNotes"
+                ],
+                "summary": "MNotesRestoreRevision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MNotes id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "revision number",
+                        "name": "revision",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "notes of every user, needs notes:all_owners",
+                        "name": "_allOwners",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.MNotes"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/m_notes/{id}/share_link": {
+            "get": {
+                "description": "Get the public links of MNotes by id with their access counts, needs the owner permission on the note",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mNotes"
+                ],
+                "summary": "MNotesShareLinks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MNotes id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "notes of every user, needs notes:all_owners",
+                        "name": "_allOwners",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Publish MNotes by id read-only at /p/{slug}, optionally until expiredOn and behind a password. The slug is only returned here. Needs the owner permission on the note",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mNotes"
+                ],
+                "summary": "MNotesCreateShareLink",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MNotes id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Expiry and password, both optional",
+                        "name": "link",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.RequestNoteShareLink"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "notes of every user, needs notes:all_owners",
+                        "name": "_allOwners",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.ResponseNoteShareLink"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/m_notes/{id}/share_link/{linkId}": {
+            "delete": {
+                "description": "Revoke a public link of MNotes by id, needs the owner permission on the note",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mNotes"
+                ],
+                "summary": "MNotesRevokeShareLink",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MNotes id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "TNoteShareLink id",
+                        "name": "linkId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "notes of every user, needs notes:all_owners",
+                        "name": "_allOwners",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/m_notes/{id}/shares": {
+            "get": {
+                "description": "Get the shares of MNotes by id, needs the owner permission on the note",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mNotes"
+                ],
+                "summary": "MNotesShares",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MNotes id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "notes of every user, needs notes:all_owners",
+                        "name": "_allOwners",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Share MNotes by id with a user or a role, the permission of an existing share is replaced. Needs the owner permission on the note",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mNotes"
+                ],
+                "summary": "MNotesShare",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MNotes id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Grantee and permission: view, edit or owner",
+                        "name": "share",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.RequestNoteShare"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "notes of every user, needs notes:all_owners",
+                        "name": "_allOwners",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/m_notes/{id}/shares/{shareId}": {
+            "delete": {
+                "description": "Revoke a share of MNotes by id, needs the owner permission on the note",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mNotes"
+                ],
+                "summary": "MNotesUnshare",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MNotes id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "TNoteShare id",
+                        "name": "shareId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "notes of every user, needs notes:all_owners",
+                        "name": "_allOwners",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/m_permission": {
+            "get": {
+                "description": "Get Page MPermission",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mPermission"
+                ],
+                "summary": "MPermissionPage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "0",
+                        "description": "page",
+                        "name": "_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "5",
+                        "description": "size",
+                        "name": "_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sort",
+                        "name": "_sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter, a JSON list of {id, value, matchMode, dataType, mode} or a group {mode, filters}. mode (AND or OR) joins a condition with the ones before it, on a group it joins the filters of the group",
+                        "name": "_filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "global filter",
+                        "name": "_q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "keyset pagination",
+                        "name": "_cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the next page",
+                        "name": "_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the previous page",
+                        "name": "_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "EXACT",
+                        "description": "EXACT, ESTIMATE or NONE",
+                        "name": "_count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include soft-deleted data, needs deleted:read",
+                        "name": "_includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only soft-deleted data, needs deleted:read",
+                        "name": "_onlyDeleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create MPermission",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mPermission"
+                ],
+                "summary": "MPermissionCreate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Add MPermission",
+                        "name": "mPermission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.MPermission"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/m_permission/delete/{id}": {
+            "put": {
+                "description": "Soft Delete MPermission by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mPermission"
+                ],
+                "summary": "MPermissionSoftDelete",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MPermission id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/m_permission/header": {
+            "get": {
+                "description": "Get MPermission header",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mPermission"
+                ],
+                "summary": "MPermissionHeader",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/m_permission/restore/{id}": {
+            "put": {
+                "description": "Restore MPermission by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mPermission"
+                ],
+                "summary": "MPermissionRestore",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MPermission id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/m_permission/{id}": {
+            "get": {
+                "description": "Get MPermission by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mPermission"
+                ],
+                "summary": "MPermissionIndex",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MPermission id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include soft-deleted data, needs deleted:read",
+                        "name": "_includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only soft-deleted data, needs deleted:read",
+                        "name": "_onlyDeleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update MPermission",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mPermission"
+                ],
+                "summary": "MPermissionUpdate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Update MPermission",
+                        "name": "mPermission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.MPermission"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MPermission id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete MPermission by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mPermission"
+                ],
+                "summary": "MPermissionDelete",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MPermission id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/m_role": {
+            "get": {
+                "description": "Get Page MRole",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mRole"
+                ],
+                "summary": "MRolePage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "0",
+                        "description": "page",
+                        "name": "_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "5",
+                        "description": "size",
+                        "name": "_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sort",
+                        "name": "_sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter, a JSON list of {id, value, matchMode, dataType, mode} or a group {mode, filters}. mode (AND or OR) joins a condition with the ones before it, on a group it joins the filters of the group",
+                        "name": "_filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "global filter",
+                        "name": "_q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "keyset pagination",
+                        "name": "_cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the next page",
+                        "name": "_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the previous page",
+                        "name": "_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "EXACT",
+                        "description": "EXACT, ESTIMATE or NONE",
+                        "name": "_count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include soft-deleted data, needs deleted:read",
+                        "name": "_includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only soft-deleted data, needs deleted:read",
+                        "name": "_onlyDeleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create MRole, granted notes:read and notes:write",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mRole"
+                ],
+                "summary": "MRoleCreate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Add MRole",
+                        "name": "mRole",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.MRole"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/m_role/authorities/{id}": {
+            "get": {
+                "description": "Preview the authorities and the permissions a user of MRole by id gets, the ones inherited through AUTH_ROLE_HIERARCHY included",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mRole"
+                ],
+                "summary": "MRoleAuthorities",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MRole id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/m_role/delete/{id}": {
+            "put": {
+                "description": "Soft Delete MRole by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mRole"
+                ],
+                "summary": "MRoleSoftDelete",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MRole id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/m_role/header": {
+            "get": {
+                "description": "Get MRole header",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mRole"
+                ],
+                "summary": "MRoleHeader",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/m_role/permissions/{id}": {
+            "get": {
+                "description": "Get the permissions of MRole by id",
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
@@ -880,7 +2946,7 @@ const docTemplate = `{
                 "tags": [
                     "mRole"
                 ],
-                "summary": "MRolePage",
+                "summary": "MRolePermissions",
                 "parameters": [
                     {
                         "type": "string",
@@ -890,36 +2956,11 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "type": "string",
-                        "default": "0",
-                        "description": "page",
-                        "name": "_page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "default": "5",
-                        "description": "size",
-                        "name": "_size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "sort",
-                        "name": "_sort",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter",
-                        "name": "_filter",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "global filter",
-                        "name": "_q",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "MRole id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -935,6 +2976,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -949,8 +2996,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Create MRole",
+            "put": {
+                "description": "Replace the permissions of MRole by id, users get them on their next login or refresh",
                 "consumes": [
                     "application/json"
                 ],
@@ -960,7 +3007,7 @@ const docTemplate = `{
                 "tags": [
                     "mRole"
                 ],
-                "summary": "MRoleCreate",
+                "summary": "MRoleSetPermissions",
                 "parameters": [
                     {
                         "type": "string",
@@ -970,12 +3017,19 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "Add MRole",
-                        "name": "mRole",
+                        "type": "integer",
+                        "description": "MRole id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Permission ids",
+                        "name": "permissions",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.MRole"
+                            "$ref": "#/definitions/request.RequestRolePermission"
                         }
                     }
                 ],
@@ -992,6 +3046,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1007,9 +3067,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/m_role/delete/{id}": {
+        "/v1/m_role/restore/{id}": {
             "put": {
-                "description": "Soft Delete MRole by id",
+                "description": "Restore MRole by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1019,7 +3079,7 @@ const docTemplate = `{
                 "tags": [
                     "mRole"
                 ],
-                "summary": "MRoleSoftDelete",
+                "summary": "MRoleRestore",
                 "parameters": [
                     {
                         "type": "string",
@@ -1064,9 +3124,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/m_role/header": {
+        "/v1/m_role/{id}": {
             "get": {
-                "description": "Get MRole header",
+                "description": "Get MRole by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1076,7 +3136,138 @@ const docTemplate = `{
                 "tags": [
                     "mRole"
                 ],
-                "summary": "MRoleHeader",
+                "summary": "MRoleIndex",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MRole id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include soft-deleted data, needs deleted:read",
+                        "name": "_includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only soft-deleted data, needs deleted:read",
+                        "name": "_onlyDeleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update MRole",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mRole"
+                ],
+                "summary": "MRoleUpdate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Update MRole",
+                        "name": "mRole",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.MRole"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MRole id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete MRole by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mRole"
+                ],
+                "summary": "MRoleDelete",
                 "parameters": [
                     {
                         "type": "string",
@@ -1084,6 +3275,13 @@ const docTemplate = `{
                         "description": "gzip",
                         "name": "Accept-Encoding",
                         "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MRole id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1114,9 +3312,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/m_role/{id}": {
+        "/v1/m_user": {
             "get": {
-                "description": "Get MRole by id",
+                "description": "Get Page MUser",
                 "consumes": [
                     "application/json"
                 ],
@@ -1124,23 +3322,85 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "mRole"
+                    "mUser"
                 ],
-                "summary": "MRoleIndex",
+                "summary": "MUserPage",
                 "parameters": [
                     {
                         "type": "string",
-                        "default": "gzip",
-                        "description": "gzip",
-                        "name": "Accept-Encoding",
-                        "in": "header"
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "0",
+                        "description": "page",
+                        "name": "_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "5",
+                        "description": "size",
+                        "name": "_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sort",
+                        "name": "_sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter, a JSON list of {id, value, matchMode, dataType, mode} or a group {mode, filters}. mode (AND or OR) joins a condition with the ones before it, on a group it joins the filters of the group",
+                        "name": "_filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "global filter",
+                        "name": "_q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "keyset pagination",
+                        "name": "_cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the next page",
+                        "name": "_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the previous page",
+                        "name": "_before",
+                        "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "MRole id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "default": "EXACT",
+                        "description": "EXACT, ESTIMATE or NONE",
+                        "name": "_count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include soft-deleted data, needs deleted:read",
+                        "name": "_includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only soft-deleted data, needs deleted:read",
+                        "name": "_onlyDeleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1170,8 +3430,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update MRole",
+            "post": {
+                "description": "Create MUser",
                 "consumes": [
                     "application/json"
                 ],
@@ -1179,9 +3439,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "mRole"
+                    "mUser"
                 ],
-                "summary": "MRoleUpdate",
+                "summary": "MUserCreate",
                 "parameters": [
                     {
                         "type": "string",
@@ -1191,20 +3451,13 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "Update MRole",
-                        "name": "mRole",
+                        "description": "Add MUser",
+                        "name": "mUser",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.MRole"
+                            "$ref": "#/definitions/model.MUser"
                         }
-                    },
-                    {
-                        "type": "integer",
-                        "description": "MRole id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -1233,9 +3486,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete MRole by id",
+            }
+        },
+        "/v1/m_user/delete/{id}": {
+            "put": {
+                "description": "Soft Delete MUser by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1243,9 +3498,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "mRole"
+                    "mUser"
                 ],
-                "summary": "MRoleDelete",
+                "summary": "MUserSoftDelete",
                 "parameters": [
                     {
                         "type": "string",
@@ -1256,7 +3511,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "MRole id",
+                        "description": "MUser id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1290,9 +3545,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/m_user": {
+        "/v1/m_user/header": {
             "get": {
-                "description": "Get Page MUser",
+                "description": "Get MUser header",
                 "consumes": [
                     "application/json"
                 ],
@@ -1302,7 +3557,7 @@ const docTemplate = `{
                 "tags": [
                     "mUser"
                 ],
-                "summary": "MUserPage",
+                "summary": "MUserHeader",
                 "parameters": [
                     {
                         "type": "string",
@@ -1310,38 +3565,6 @@ const docTemplate = `{
                         "description": "gzip",
                         "name": "Accept-Encoding",
                         "in": "header"
-                    },
-                    {
-                        "type": "string",
-                        "default": "0",
-                        "description": "page",
-                        "name": "_page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "default": "5",
-                        "description": "size",
-                        "name": "_size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "sort",
-                        "name": "_sort",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter",
-                        "name": "_filter",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "global filter",
-                        "name": "_q",
-                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1370,9 +3593,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create MUser",
+            }
+        },
+        "/v1/m_user/restore/{id}": {
+            "put": {
+                "description": "Restore MUser by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1382,7 +3607,7 @@ const docTemplate = `{
                 "tags": [
                     "mUser"
                 ],
-                "summary": "MUserCreate",
+                "summary": "MUserRestore",
                 "parameters": [
                     {
                         "type": "string",
@@ -1392,13 +3617,11 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "Add MUser",
-                        "name": "mUser",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.MUser"
-                        }
+                        "type": "integer",
+                        "description": "MUser id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1429,9 +3652,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/m_user/delete/{id}": {
+        "/v1/m_user/revoke_sessions/{id}": {
             "put": {
-                "description": "Soft Delete MUser by id",
+                "description": "Revoke every session of MUser by id, needs users:write",
                 "consumes": [
                     "application/json"
                 ],
@@ -1441,7 +3664,7 @@ const docTemplate = `{
                 "tags": [
                     "mUser"
                 ],
-                "summary": "MUserSoftDelete",
+                "summary": "MUserRevokeSessions",
                 "parameters": [
                     {
                         "type": "string",
@@ -1471,6 +3694,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1486,9 +3715,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/m_user/header": {
-            "get": {
-                "description": "Get MUser header",
+        "/v1/m_user/unlock/{id}": {
+            "put": {
+                "description": "Unlock MUser by id and reset its failed logins, needs users:write",
                 "consumes": [
                     "application/json"
                 ],
@@ -1498,7 +3727,7 @@ const docTemplate = `{
                 "tags": [
                     "mUser"
                 ],
-                "summary": "MUserHeader",
+                "summary": "MUserUnlock",
                 "parameters": [
                     {
                         "type": "string",
@@ -1506,6 +3735,13 @@ const docTemplate = `{
                         "description": "gzip",
                         "name": "Accept-Encoding",
                         "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "MUser id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1521,6 +3757,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1563,6 +3805,18 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include soft-deleted data, needs deleted:read",
+                        "name": "_includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only soft-deleted data, needs deleted:read",
+                        "name": "_onlyDeleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1755,7 +4009,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "filter",
+                        "description": "filter, a JSON list of {id, value, matchMode, dataType, mode} or a group {mode, filters}. mode (AND or OR) joins a condition with the ones before it, on a group it joins the filters of the group",
                         "name": "_filter",
                         "in": "query"
                     },
@@ -1764,6 +4018,43 @@ const docTemplate = `{
                         "description": "global filter",
                         "name": "_q",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "keyset pagination",
+                        "name": "_cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the next page",
+                        "name": "_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the previous page",
+                        "name": "_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "EXACT",
+                        "description": "EXACT, ESTIMATE or NONE",
+                        "name": "_count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include soft-deleted data, needs deleted:read",
+                        "name": "_includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only soft-deleted data, needs deleted:read",
+                        "name": "_onlyDeleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1958,6 +4249,63 @@ const docTemplate = `{
                 }
             }
         },
+        "/v1/t_reset_password/restore/{id}": {
+            "put": {
+                "description": "Restore TResetPassword by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tResetPassword"
+                ],
+                "summary": "TResetPasswordRestore",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "TResetPassword id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/t_reset_password/{id}": {
             "get": {
                 "description": "Get TResetPassword by id",
@@ -1985,6 +4333,18 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include soft-deleted data, needs deleted:read",
+                        "name": "_includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only soft-deleted data, needs deleted:read",
+                        "name": "_onlyDeleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -2157,34 +4517,71 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "default": "0",
-                        "description": "page",
-                        "name": "_page",
+                        "default": "0",
+                        "description": "page",
+                        "name": "_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "5",
+                        "description": "size",
+                        "name": "_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sort",
+                        "name": "_sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter, a JSON list of {id, value, matchMode, dataType, mode} or a group {mode, filters}. mode (AND or OR) joins a condition with the ones before it, on a group it joins the filters of the group",
+                        "name": "_filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "global filter",
+                        "name": "_q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "keyset pagination",
+                        "name": "_cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the next page",
+                        "name": "_after",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "default": "5",
-                        "description": "size",
-                        "name": "_size",
+                        "description": "cursor of the previous page",
+                        "name": "_before",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "sort",
-                        "name": "_sort",
+                        "default": "EXACT",
+                        "description": "EXACT, ESTIMATE or NONE",
+                        "name": "_count",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "filter",
-                        "name": "_filter",
+                        "type": "boolean",
+                        "description": "include soft-deleted data, needs deleted:read",
+                        "name": "_includeDeleted",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "global filter",
-                        "name": "_q",
+                        "type": "boolean",
+                        "description": "only soft-deleted data, needs deleted:read",
+                        "name": "_onlyDeleted",
                         "in": "query"
                     }
                 ],
@@ -2380,6 +4777,63 @@ const docTemplate = `{
                 }
             }
         },
+        "/v1/t_token/restore/{id}": {
+            "put": {
+                "description": "Restore TToken by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tToken"
+                ],
+                "summary": "TTokenRestore",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gzip",
+                        "description": "gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "TToken id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/t_token/{id}": {
             "get": {
                 "description": "Get TToken by id",
@@ -2407,6 +4861,18 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include soft-deleted data, needs deleted:read",
+                        "name": "_includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only soft-deleted data, needs deleted:read",
+                        "name": "_onlyDeleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -2653,6 +5119,50 @@ const docTemplate = `{
                 }
             }
         },
+        "model.MPermission": {
+            "type": "object",
+            "required": [
+                "code",
+                "id"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "createdBy": {
+                    "type": "integer"
+                },
+                "createdOn": {
+                    "type": "string",
+                    "example": "2024-02-16 10:33:10"
+                },
+                "deletedBy": {
+                    "type": "integer"
+                },
+                "deletedOn": {
+                    "type": "string",
+                    "example": "2024-02-16 10:33:10"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "isDelete": {
+                    "type": "boolean"
+                },
+                "modifiedBy": {
+                    "type": "integer"
+                },
+                "modifiedOn": {
+                    "type": "string",
+                    "example": "2024-02-16 10:33:10"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
         "model.MRole": {
             "type": "object",
             "required": [
@@ -2739,6 +5249,10 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2024-02-16 10:33:10"
                 },
+                "lockedOn": {
+                    "type": "string",
+                    "example": "2024-02-16 10:33:10"
+                },
                 "loginAttempt": {
                     "type": "integer"
                 },
@@ -2764,6 +5278,36 @@ const docTemplate = `{
                 }
             }
         },
+        "model.TNoteRevision": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "integer"
+                },
+                "createdOn": {
+                    "type": "string",
+                    "example": "2024-02-16 10:33:10"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "noteId": {
+                    "type": "integer"
+                },
+                "restoredFrom": {
+                    "type": "integer"
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
         "model.TResetPassword": {
             "type": "object",
             "required": [
@@ -2797,17 +5341,12 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2024-02-16 10:33:10"
                 },
-                "newPassword": {
-                    "type": "string",
-                    "maxLength": 255
-                },
-                "oldPassword": {
-                    "type": "string",
-                    "maxLength": 255
-                },
                 "resetFor": {
                     "type": "string",
                     "maxLength": 20
+                },
+                "userId": {
+                    "type": "integer"
                 }
             }
         },
@@ -2860,7 +5399,7 @@ const docTemplate = `{
                 },
                 "token": {
                     "type": "string",
-                    "maxLength": 50
+                    "maxLength": 64
                 },
                 "usedFor": {
                     "type": "string",
@@ -2871,6 +5410,76 @@ const docTemplate = `{
                 }
             }
         },
+        "request.RequestNoteShare": {
+            "type": "object",
+            "required": [
+                "permission"
+            ],
+            "properties": {
+                "permission": {
+                    "type": "string",
+                    "enum": [
+                        "view",
+                        "edit",
+                        "owner"
+                    ]
+                },
+                "roleId": {
+                    "type": "integer"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "request.RequestNoteShareLink": {
+            "type": "object",
+            "properties": {
+                "expiredOn": {
+                    "type": "string",
+                    "example": "2024-02-16 10:33:10"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 72
+                }
+            }
+        },
+        "request.RequestRolePermission": {
+            "type": "object",
+            "required": [
+                "permissionIds"
+            ],
+            "properties": {
+                "permissionIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "response.DiffLine": {
+            "type": "object",
+            "properties": {
+                "newLine": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "oldLine": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "op": {
+                    "type": "string",
+                    "example": "insert"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "text"
+                }
+            }
+        },
         "response.Response": {
             "type": "object",
             "properties": {
@@ -2894,6 +5503,77 @@ const docTemplate = `{
                     "example": "2024-02-16 10:33:10"
                 }
             }
+        },
+        "response.ResponseNoteRevisionDiff": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.DiffLine"
+                    }
+                },
+                "from": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "noteId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "title": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.DiffLine"
+                    }
+                },
+                "to": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "response.ResponseNoteShareLink": {
+            "type": "object",
+            "properties": {
+                "expiredOn": {
+                    "type": "string",
+                    "example": "2024-02-16 10:33:10"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "path": {
+                    "type": "string",
+                    "example": "/p/slug"
+                },
+                "slug": {
+                    "type": "string",
+                    "example": "slug"
+                }
+            }
+        },
+        "response.ResponseSharedNote": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "content"
+                },
+                "createdOn": {
+                    "type": "string",
+                    "example": "2024-02-16 10:33:10"
+                },
+                "modifiedOn": {
+                    "type": "string",
+                    "example": "2024-02-16 10:33:10"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "title"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -2912,15 +5592,15 @@ const docTemplate = `{
 // SwaggerInfo holds exported Swagger Info so clients can modify it
 var SwaggerInfo = &swag.Spec{
 	Version:          "1.0",
-	Host:             "assusa456u.local:8802",
+	Host:             "localhost:8802",
 	BasePath:         "/",
 	Schemes:          []string{},
 	Title:            "GIN CRUD",
 	Description:      "This is a sample server celler server.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
-	
-	
+	LeftDelim:        "{{",
+	RightDelim:       "}}",
 }
 
 func init() {
@@ -1,12 +1,15 @@
 package initializer
 
 import (
+	"errors"
 	"io"
 	"log"
 	"os"
 	"time"
 
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
@@ -36,16 +39,12 @@ func ConnectToDB() {
 		},
 	)
 
-	// refer https://github.com/go-sql-driver/mysql#dsn-data-source-name for details
-	dsn := os.Getenv("DB_URL")
-	DB, err = gorm.Open(mysql.New(mysql.Config{
-		DSN:                       dsn,   // data source name
-		DefaultStringSize:         256,   // default size for string fields
-		DisableDatetimePrecision:  true,  // disable datetime precision, which not supported before MySQL 5.6
-		DontSupportRenameIndex:    true,  // drop & create when rename index, rename index not supported before MySQL 5.7, MariaDB
-		DontSupportRenameColumn:   true,  // `change` when rename column, rename column not supported before MySQL 8, MariaDB
-		SkipInitializeWithVersion: false, // auto configure based on currently MySQL version
-	}), &gorm.Config{
+	dialector, err := newDialector(os.Getenv("DB_DRIVER"), os.Getenv("DB_URL"))
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	DB, err = gorm.Open(dialector, &gorm.Config{
 		Logger: multiLogger,
 	})
 	if err != nil {
@@ -65,4 +64,34 @@ func ConnectToDB() {
 	// SetConnMaxLifetime sets the maximum amount of time a connection may be reused.
 	sqlDB.SetConnMaxLifetime(time.Minute)
 
+	if DB.Dialector.Name() == "sqlite" {
+		// sqlite allows one writer, and an in-memory database lives only as
+		// long as its connection
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+	}
+}
+
+// newDialector returns the gorm dialector of driver (mysql, postgres or
+// sqlite), mysql when driver is empty. The sqlite driver needs cgo.
+func newDialector(driver string, dsn string) (gorm.Dialector, error) {
+	switch driver {
+	case "", "mysql":
+		// refer https://github.com/go-sql-driver/mysql#dsn-data-source-name for details
+		return mysql.New(mysql.Config{
+			DSN:                       dsn,   // data source name
+			DefaultStringSize:         256,   // default size for string fields
+			DisableDatetimePrecision:  true,  // disable datetime precision, which not supported before MySQL 5.6
+			DontSupportRenameIndex:    true,  // drop & create when rename index, rename index not supported before MySQL 5.7, MariaDB
+			DontSupportRenameColumn:   true,  // `change` when rename column, rename column not supported before MySQL 8, MariaDB
+			SkipInitializeWithVersion: false, // auto configure based on currently MySQL version
+		}), nil
+	case "postgres":
+		// e.g. host=localhost user=gin password=gin dbname=gin_notes port=5432 sslmode=disable
+		return postgres.Open(dsn), nil
+	case "sqlite":
+		// e.g. gin_notes.db or file::memory:?cache=shared
+		return sqlite.Open(dsn), nil
+	}
+	return nil, errors.New("DB_DRIVER " + driver + " is not supported, use mysql, postgres or sqlite")
 }
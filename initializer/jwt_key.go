@@ -0,0 +1,24 @@
+package initializer
+
+import (
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/amsatrio/gin_notes/util"
+)
+
+// JwtKeyInit loads the JWT keys at start, a missing or invalid key file stops
+// the app instead of failing every login.
+func JwtKeyInit() {
+	if os.Getenv("AUTH_JWT_ENABLE") != "true" {
+		return
+	}
+
+	keyring, err := util.LoadJwtKeyring()
+	if err != nil {
+		log.Fatal("Failed to load jwt keys: " + err.Error())
+	}
+	util.SetJwtKeyring(keyring)
+	util.Log("INFO", "initializer", "JwtKeyInit", keyring.Signing.Method.Alg()+" jwt keys loaded, "+strconv.Itoa(len(keyring.Keys))+" verification key(s)")
+}
@@ -0,0 +1,9 @@
+package initializer
+
+import "github.com/amsatrio/gin_notes/util"
+
+// MailerInit picks the mailer of MAIL_DRIVER at start, so a missing or unsafe
+// mail setup is logged before the first reset is asked for.
+func MailerInit() {
+	util.GetMailer()
+}
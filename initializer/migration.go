@@ -0,0 +1,23 @@
+package initializer
+
+import (
+	"log"
+	"os"
+
+	"github.com/amsatrio/gin_notes/migration"
+)
+
+// MigrateDB applies the pending schema migrations when DB_MIGRATE is true.
+func MigrateDB() {
+	if os.Getenv("DB_MIGRATE") != "true" {
+		return
+	}
+
+	done, err := migration.Up(DB, 0)
+	for _, m := range done {
+		log.Printf("migration applied: %s %s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		log.Fatal("Failed to migrate database: " + err.Error())
+	}
+}
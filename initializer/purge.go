@@ -0,0 +1,44 @@
+package initializer
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/amsatrio/gin_notes/util"
+)
+
+// PurgeInit runs purge every SOFT_DELETE_PURGE_INTERVAL_MS (default one hour)
+// for the rows soft-deleted more than SOFT_DELETE_RETENTION_DAYS ago. An empty
+// or 0 retention keeps the deleted rows forever.
+func PurgeInit(purge func(context context.Context, deletedBefore time.Time) error) {
+	retentionDays, err := strconv.Atoi(os.Getenv("SOFT_DELETE_RETENTION_DAYS"))
+	if err != nil || retentionDays <= 0 {
+		return
+	}
+
+	interval := time.Hour
+	intervalMs, err := strconv.Atoi(os.Getenv("SOFT_DELETE_PURGE_INTERVAL_MS"))
+	if err == nil && intervalMs > 0 {
+		interval = time.Duration(intervalMs) * time.Millisecond
+	}
+
+	retention := time.Duration(retentionDays) * 24 * time.Hour
+	run := func() {
+		deletedBefore := time.Now().Add(-retention)
+		if err := purge(context.Background(), deletedBefore); err != nil {
+			util.Log("ERROR", "initializer", "PurgeInit", "purge failed: "+err.Error())
+		}
+	}
+
+	util.Log("INFO", "initializer", "PurgeInit", "purge soft-deleted data older than "+strconv.Itoa(retentionDays)+" day(s) every "+interval.String())
+	go func() {
+		run()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			run()
+		}
+	}()
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/gin-contrib/sessions/cookie"
 	"github.com/gin-gonic/gin"
 
+	"github.com/amsatrio/gin_notes/controller"
 	"github.com/amsatrio/gin_notes/docs"
 	"github.com/amsatrio/gin_notes/initializer"
 	"github.com/amsatrio/gin_notes/middleware"
@@ -20,7 +21,10 @@ func init() {
 	initializer.LoadEnvironmentVariables()
 	initializer.ConnectToDB()
 	initializer.LoggerInit()
+	initializer.MigrateDB()
 	initializer.RedisInit()
+	initializer.JwtKeyInit()
+	initializer.MailerInit()
 }
 
 //	@title			GIN CRUD
@@ -85,6 +89,7 @@ func main() {
 	r.Use(middleware.RedisMiddleware)
 
 	route.AppRoutes(r)
+	initializer.PurgeInit(controller.PurgeDeleted)
 
 	port := os.Getenv("PORT")
 	fmt.Println("listen and serve on localhost port " + port)
@@ -66,7 +66,8 @@ func CustomErrorApiMiddleware() gin.HandlerFunc {
 
 		switch errorValu {
 		// status 500
-		case constant.ErrorRedisDeleteFailed:
+		case constant.ErrorRedisDeleteFailed,
+			constant.ErrorRedisReadFailed:
 			er := response.Response{
 				Data:      errorMessage,
 				Status:    http.StatusInternalServerError,
@@ -90,7 +91,11 @@ func CustomErrorApiMiddleware() gin.HandlerFunc {
 			constant.ErrorAuthorizationIsEmpty,
 			constant.ErrorAuthorizationHeaderIsInvalid,
 			constant.ErrorAuthorizationTokenExpired,
-			constant.ErrorAuthenticationFailed:
+			constant.ErrorAuthorizationTokenRevoked,
+			constant.ErrorAuthenticationFailed,
+			constant.ErrorPasswordExpired,
+			constant.ErrorTokenReused,
+			constant.ErrorSharePasswordInvalid:
 			er := response.Response{
 				Data:      errorMessage,
 				Status:    http.StatusUnauthorized,
@@ -100,6 +105,17 @@ func CustomErrorApiMiddleware() gin.HandlerFunc {
 			}
 			c.AbortWithStatusJSON(er.Status, er)
 
+		// status 423
+		case constant.ErrorAccountLocked:
+			er := response.Response{
+				Data:      errorMessage,
+				Status:    http.StatusLocked,
+				Message:   fmt.Sprintf("%v", errorValu),
+				Timestamp: response.JSONTime{Time: time.Now()},
+				Path:      c.FullPath(),
+			}
+			c.AbortWithStatusJSON(er.Status, er)
+
 		// status 403
 		case constant.ErrorPermissionDenied:
 			er := response.Response{
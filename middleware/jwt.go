@@ -8,6 +8,8 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/amsatrio/gin_notes/constant"
+	"github.com/amsatrio/gin_notes/initializer"
+	"github.com/amsatrio/gin_notes/service"
 	"github.com/amsatrio/gin_notes/util"
 )
 
@@ -22,8 +24,12 @@ func JwtMiddleware() gin.HandlerFunc {
 
 		whiteListPath := []string{
 			"/doc/swagger-ui",
+			"/.well-known/jwks.json",
+			"/p/:slug",
 			"/v1/auth/login",
 			"/v1/auth/refresh_token",
+			"/v1/auth/forgot_password",
+			"/v1/auth/reset_password",
 			"/v1/health/public",
 			"/v1/health/status",
 		}
@@ -98,9 +104,45 @@ func JwtAuthentication(c *gin.Context) {
 		return
 	}
 
+	// check session, a logout or a revoke ends it before the token expires
+	sessionId := util.JwtGetSessionId(jwt_claim)
+	if sessionId != "" {
+		refreshTokenService := service.NewRefreshTokenServiceImpl(initializer.DB)
+		active, err := refreshTokenService.IsSessionActive(c, sessionId)
+		if err != nil {
+			util.Log("ERROR", "middleware", "JwtAuthentication", "check session error: "+err.Error())
+		}
+		if err != nil || !active {
+			util.Log("INFO", "middleware", "JwtAuthentication", "session is revoked")
+			c.Set(constant.ERROR_KEY, constant.ErrorAuthorizationTokenExpired)
+			c.Abort()
+			return
+		}
+		c.Set("session_id", sessionId)
+	}
+
+	// check denylist, a token that cannot be checked is refused
+	tokenDenylistService := service.NewTokenDenylistServiceImpl(initializer.RDB)
+	denied, err := tokenDenylistService.IsDenied(c, util.JwtGetUserName(jwt_claim), util.JwtGetTokenId(jwt_claim), util.JwtGetIssuedAt(jwt_claim))
+	if err != nil {
+		util.Log("ERROR", "middleware", "JwtAuthentication", "check denylist error: "+err.Error())
+		c.Set(constant.ERROR_KEY, constant.ErrorRedisReadFailed)
+		c.Abort()
+		return
+	}
+	if denied {
+		util.Log("INFO", "middleware", "JwtAuthentication", "token is revoked")
+		c.Set(constant.ERROR_KEY, constant.ErrorAuthorizationTokenRevoked)
+		c.Abort()
+		return
+	}
+
 	// get authorities
 	authorities := util.JwtGetAuthorities(jwt_claim)
 	c.Set("authorities", authorities)
+
+	// get permissions
+	c.Set("permissions", util.JwtGetPermissions(jwt_claim))
 	//util.Log("INFO", "middleware", "JwtAuthentication", "authorities: "+fmt.Sprintf("%v", authorities))
 
 	// get username
@@ -112,67 +154,34 @@ func JwtAuthentication(c *gin.Context) {
 	c.Next()
 
 }
-func JwtAuthorizationAdmin(c *gin.Context) {
-	authorities := c.MustGet("authorities").([]string)
-
-	for _, v := range authorities {
-		if v == "ROLE_ADMIN" {
-			c.Next()
-			return
-		}
-	}
-
-	util.Log("INFO", "middleware", "JwtAuthorizationAdmin", "authorities invalid")
-	c.Set(constant.ERROR_KEY, constant.ErrorAuthorizationHeaderIsInvalid)
-	c.Abort()
-
-	_ = authorities
-}
-func JwtAuthorizationPasien(c *gin.Context) {
-	authorities := c.MustGet("authorities").([]string)
 
-	for _, v := range authorities {
-		if v == "ROLE_PASIEN" {
-			c.Next()
-			return
-		}
+// HasPermission reports whether the token of the request grants permission.
+// Every request is trusted when AUTH_JWT_ENABLE is not true.
+func HasPermission(c *gin.Context, permission string) bool {
+	if os.Getenv("AUTH_JWT_ENABLE") != "true" {
+		return true
 	}
 
-	util.Log("INFO", "middleware", "JwtAuthorizationPasien", "authorities invalid")
-	c.Set(constant.ERROR_KEY, constant.ErrorAuthorizationHeaderIsInvalid)
-	c.Abort()
-
-	_ = authorities
-}
-func JwtAuthorizationFaskes(c *gin.Context) {
-	authorities := c.MustGet("authorities").([]string)
-
-	for _, v := range authorities {
-		if v == "ROLE_FASKES" {
-			c.Next()
-			return
+	for _, v := range c.GetStringSlice("permissions") {
+		if v == permission {
+			return true
 		}
 	}
-
-	util.Log("INFO", "middleware", "JwtAuthorizationFaskes", "authorities invalid")
-	c.Set(constant.ERROR_KEY, constant.ErrorAuthorizationHeaderIsInvalid)
-	c.Abort()
-
-	_ = authorities
+	return false
 }
-func JwtAuthorizationDokter(c *gin.Context) {
-	authorities := c.MustGet("authorities").([]string)
 
-	for _, v := range authorities {
-		if v == "ROLE_DOKTER" {
-			c.Next()
+// RequirePermission returns a handler aborting the request with
+// ErrorPermissionDenied unless HasPermission, e.g.
+//
+//	v1.PUT("/m_user/unlock/:id", middleware.RequirePermission("users:write"), controller.MUserUnlock)
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasPermission(c, permission) {
+			util.Log("INFO", "middleware", "RequirePermission", "permission "+permission+" is missing")
+			c.Set(constant.ERROR_KEY, constant.ErrorPermissionDenied)
+			c.Abort()
 			return
 		}
+		c.Next()
 	}
-
-	util.Log("INFO", "middleware", "JwtAuthorizationDokter", "authorities invalid")
-	c.Set(constant.ERROR_KEY, constant.ErrorAuthorizationHeaderIsInvalid)
-	c.Abort()
-
-	_ = authorities
 }
@@ -58,6 +58,21 @@ func RedisMiddleware(c *gin.Context) {
 		c.Next()
 		return
 	}
+	// the keys change on a rotation, the cache has no expiry
+	if strings.HasPrefix(cacheKey, "/.well-known") {
+		c.Next()
+		return
+	}
+	// a share link counts every access and is revoked at any time
+	if strings.HasPrefix(c.FullPath(), "/p/") {
+		c.Next()
+		return
+	}
+	// deleted data needs deleted:read, a cached response would skip that check
+	if c.Query("_includeDeleted") != "" || c.Query("_onlyDeleted") != "" {
+		c.Next()
+		return
+	}
 
 	// check http method
 	method := c.Request.Method
@@ -76,6 +91,14 @@ func RedisMiddleware(c *gin.Context) {
 		return
 	}
 
+	// the cache runs before the permission and ownership checks of the
+	// routes, a response is only served back to the user it was made for
+	if os.Getenv("AUTH_JWT_ENABLE") == "true" {
+		cacheKey += ":username=" + c.GetString("username") +
+			":authorities=" + strings.Join(c.GetStringSlice("authorities"), ",") +
+			":permissions=" + strings.Join(c.GetStringSlice("permissions"), ",")
+	}
+
 	body, err := io.ReadAll(c.Request.Body)
 	if err == nil {
 		id, _ := extractIDFromBody(body)
@@ -146,6 +169,16 @@ func extractIDFromBody(body []byte) (float64, error) {
 	return id, nil
 }
 
+// RedisEvict drops the cached responses of the paths starting with prefix, for
+// a write that changes who may read them.
+func RedisEvict(prefix string) error {
+	if os.Getenv("REDIS_ENABLE") == "false" {
+		return nil
+	}
+	_, err := deleteRedisDataContainsKey(prefix + "*")
+	return err
+}
+
 func deleteRedisDataContainsKey(kePattern string) (count int, err error) {
 	var foundedRecordCount int = 0
 	iter := initializer.RDB.Scan(initializer.RCTX, 0, kePattern, 0).Iterator()
@@ -0,0 +1,107 @@
+// Command migrate applies the schema migrations of the migration package to
+// the database of DB_URL.
+//
+//	go run migrate/migrate.go up [n]       apply all (or n) pending migrations
+//	go run migrate/migrate.go down [n]     revert the last (or n) migrations
+//	go run migrate/migrate.go status       list migrations and their state
+//	go run migrate/migrate.go create name  write an empty migration
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/amsatrio/gin_notes/initializer"
+	"github.com/amsatrio/gin_notes/migration"
+)
+
+const usage = `usage: migrate <command>
+
+commands:
+  up [n]        apply all pending migrations, or the next n
+  down [n]      revert the last applied migration, or the last n
+  status        list migrations and whether they are applied
+  create name   write an empty migration into migration/`
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "migrate: "+err.Error())
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n%s", usage)
+	}
+
+	switch args[0] {
+	case "create":
+		if len(args) != 2 {
+			return fmt.Errorf("create needs a name\n%s", usage)
+		}
+		path, err := migration.Create("migration", args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Println("created " + path)
+		return nil
+
+	case "up", "down":
+		steps := 0
+		if len(args) > 2 {
+			return fmt.Errorf("too many arguments\n%s", usage)
+		}
+		if len(args) == 2 {
+			var err error
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("n must be a positive number")
+			}
+		}
+
+		connect()
+		run := migration.Up
+		verb := "applied"
+		if args[0] == "down" {
+			run, verb = migration.Down, "reverted"
+		}
+		done, err := run(initializer.DB, steps)
+		for _, m := range done {
+			fmt.Println(verb + " " + m.Version + " " + m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Println("nothing to do")
+		}
+		return nil
+
+	case "status":
+		connect()
+		statuses, err := migration.GetStatus(initializer.DB)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedOn.Format("2006-01-02 15:04:05")
+			}
+			if status.Missing {
+				state += " (missing in this build)"
+			}
+			fmt.Printf("%s  %-40s %s\n", status.Version, status.Name, state)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown command %s\n%s", args[0], usage)
+}
+
+func connect() {
+	initializer.LoadEnvironmentVariables()
+	initializer.ConnectToDB()
+}
@@ -0,0 +1,40 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(Migration{
+		Version: "20240216000001",
+		Name:    "create_m_biodata",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&mBiodata20240216000001{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("m_biodata")
+		},
+	})
+}
+
+type mBiodata20240216000001 struct {
+	Id          uint   `gorm:"primaryKey;autoIncrement"`
+	Fullname    string `gorm:"size:255"`
+	MobilePhone string `gorm:"size:15"`
+	Image       []byte
+	ImagePath   string `gorm:"size:255"`
+
+	CreatedBy  uint `gorm:"not null"`
+	CreatedOn  *time.Time
+	ModifiedBy uint
+	ModifiedOn *time.Time
+	DeletedBy  uint
+	DeletedOn  *time.Time
+	IsDelete   *bool `gorm:"default:false"`
+}
+
+func (mBiodata20240216000001) TableName() string {
+	return "m_biodata"
+}
@@ -0,0 +1,39 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(Migration{
+		Version: "20240216000002",
+		Name:    "create_m_role",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&mRole20240216000002{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("m_role")
+		},
+	})
+}
+
+type mRole20240216000002 struct {
+	Id    uint   `gorm:"primaryKey;autoIncrement"`
+	Name  string `gorm:"size:20"`
+	Code  string `gorm:"size:20"`
+	Level int
+
+	CreatedBy  uint `gorm:"not null"`
+	CreatedOn  *time.Time
+	ModifiedBy uint
+	ModifiedOn *time.Time
+	DeletedBy  uint
+	DeletedOn  *time.Time
+	IsDelete   *bool `gorm:"default:false"`
+}
+
+func (mRole20240216000002) TableName() string {
+	return "m_role"
+}
@@ -0,0 +1,43 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(Migration{
+		Version: "20240216000003",
+		Name:    "create_m_user",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&mUser20240216000003{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("m_user")
+		},
+	})
+}
+
+type mUser20240216000003 struct {
+	Id           uint   `gorm:"primaryKey;autoIncrement"`
+	BiodataId    uint   `gorm:"index"`
+	RoleId       uint   `gorm:"index"`
+	Email        string `gorm:"size:100;uniqueIndex"`
+	Password     string `gorm:"size:255"`
+	LoginAttempt int
+	IsLocked     bool
+	LastLogin    *time.Time
+
+	CreatedBy  uint `gorm:"not null"`
+	CreatedOn  *time.Time
+	ModifiedBy uint
+	ModifiedOn *time.Time
+	DeletedBy  uint
+	DeletedOn  *time.Time
+	IsDelete   *bool `gorm:"default:false"`
+}
+
+func (mUser20240216000003) TableName() string {
+	return "m_user"
+}
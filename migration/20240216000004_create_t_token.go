@@ -0,0 +1,42 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(Migration{
+		Version: "20240216000004",
+		Name:    "create_t_token",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&tToken20240216000004{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("t_token")
+		},
+	})
+}
+
+type tToken20240216000004 struct {
+	Id        uint   `gorm:"primaryKey;autoIncrement"`
+	Email     string `gorm:"size:100"`
+	UserId    uint   `gorm:"index"`
+	Token     string `gorm:"size:50"`
+	ExpiredOn *time.Time
+	IsExpired bool
+	UsedFor   string `gorm:"size:20"`
+
+	CreatedBy  uint `gorm:"not null"`
+	CreatedOn  *time.Time
+	ModifiedBy uint
+	ModifiedOn *time.Time
+	DeletedBy  uint
+	DeletedOn  *time.Time
+	IsDelete   *bool `gorm:"default:false"`
+}
+
+func (tToken20240216000004) TableName() string {
+	return "t_token"
+}
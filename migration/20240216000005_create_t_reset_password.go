@@ -0,0 +1,39 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(Migration{
+		Version: "20240216000005",
+		Name:    "create_t_reset_password",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&tResetPassword20240216000005{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("t_reset_password")
+		},
+	})
+}
+
+type tResetPassword20240216000005 struct {
+	Id          uint   `gorm:"primaryKey;autoIncrement"`
+	OldPassword string `gorm:"size:255"`
+	NewPassword string `gorm:"size:255"`
+	ResetFor    string `gorm:"size:20"`
+
+	CreatedBy  uint `gorm:"not null"`
+	CreatedOn  *time.Time
+	ModifiedBy uint
+	ModifiedOn *time.Time
+	DeletedBy  uint
+	DeletedOn  *time.Time
+	IsDelete   *bool `gorm:"default:false"`
+}
+
+func (tResetPassword20240216000005) TableName() string {
+	return "t_reset_password"
+}
@@ -0,0 +1,38 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(Migration{
+		Version: "20240216000006",
+		Name:    "create_m_notes",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&mNotes20240216000006{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("m_notes")
+		},
+	})
+}
+
+type mNotes20240216000006 struct {
+	Id      uint   `gorm:"primaryKey;autoIncrement"`
+	Title   string `gorm:"size:200"`
+	Content string `gorm:"type:text"`
+
+	CreatedBy  uint `gorm:"not null"`
+	CreatedOn  *time.Time
+	ModifiedBy uint
+	ModifiedOn *time.Time
+	DeletedBy  uint
+	DeletedOn  *time.Time
+	IsDelete   *bool `gorm:"default:false"`
+}
+
+func (mNotes20240216000006) TableName() string {
+	return "m_notes"
+}
@@ -0,0 +1,28 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(Migration{
+		Version: "20261018000001",
+		Name:    "add_m_user_locked_on",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AddColumn(&mUser20261018000001{}, "LockedOn")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&mUser20261018000001{}, "LockedOn")
+		},
+	})
+}
+
+type mUser20261018000001 struct {
+	LockedOn *time.Time
+}
+
+func (mUser20261018000001) TableName() string {
+	return "m_user"
+}
@@ -0,0 +1,31 @@
+package migration
+
+import "gorm.io/gorm"
+
+func init() {
+	Register(Migration{
+		Version: "20261018000002",
+		Name:    "widen_t_token_token",
+		Up: func(tx *gorm.DB) error {
+			// a sha256 hex digest of the token is stored instead of the token
+			if err := tx.Migrator().AlterColumn(&tToken20261018000002{}, "Token"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateIndex(&tToken20261018000002{}, "Token")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropIndex(&tToken20261018000002{}, "Token"); err != nil {
+				return err
+			}
+			return tx.Migrator().AlterColumn(&tToken20240216000004{}, "Token")
+		},
+	})
+}
+
+type tToken20261018000002 struct {
+	Token string `gorm:"size:64;index"`
+}
+
+func (tToken20261018000002) TableName() string {
+	return "t_token"
+}
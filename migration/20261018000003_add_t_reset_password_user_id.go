@@ -0,0 +1,24 @@
+package migration
+
+import "gorm.io/gorm"
+
+func init() {
+	Register(Migration{
+		Version: "20261018000003",
+		Name:    "add_t_reset_password_user_id",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AddColumn(&tResetPassword20261018000003{}, "UserId")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&tResetPassword20261018000003{}, "UserId")
+		},
+	})
+}
+
+type tResetPassword20261018000003 struct {
+	UserId uint
+}
+
+func (tResetPassword20261018000003) TableName() string {
+	return "t_reset_password"
+}
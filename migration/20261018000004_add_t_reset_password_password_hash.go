@@ -0,0 +1,28 @@
+package migration
+
+import "gorm.io/gorm"
+
+func init() {
+	Register(Migration{
+		Version: "20261018000004",
+		Name:    "add_t_reset_password_password_hash",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&tResetPassword20261018000004{}, "PasswordHash"); err != nil {
+				return err
+			}
+			// keep the rows written as hashes, plaintext passwords are dropped
+			return tx.Exec("UPDATE t_reset_password SET password_hash = new_password WHERE new_password LIKE '$%'").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&tResetPassword20261018000004{}, "PasswordHash")
+		},
+	})
+}
+
+type tResetPassword20261018000004 struct {
+	PasswordHash string `gorm:"size:255"`
+}
+
+func (tResetPassword20261018000004) TableName() string {
+	return "t_reset_password"
+}
@@ -0,0 +1,25 @@
+package migration
+
+import "gorm.io/gorm"
+
+func init() {
+	Register(Migration{
+		Version: "20261018000005",
+		Name:    "drop_t_reset_password_old_password",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&tResetPassword20261018000005{}, "OldPassword")
+		},
+		Down: func(tx *gorm.DB) error {
+			// the dropped values are not restored
+			return tx.Migrator().AddColumn(&tResetPassword20261018000005{}, "OldPassword")
+		},
+	})
+}
+
+type tResetPassword20261018000005 struct {
+	OldPassword string `gorm:"size:255"`
+}
+
+func (tResetPassword20261018000005) TableName() string {
+	return "t_reset_password"
+}
@@ -0,0 +1,24 @@
+package migration
+
+import "gorm.io/gorm"
+
+func init() {
+	Register(Migration{
+		Version: "20261018000007",
+		Name:    "add_t_reset_password_user_id_index",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateIndex(&tResetPassword20261018000007{}, "UserId")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropIndex(&tResetPassword20261018000007{}, "UserId")
+		},
+	})
+}
+
+type tResetPassword20261018000007 struct {
+	UserId uint `gorm:"index"`
+}
+
+func (tResetPassword20261018000007) TableName() string {
+	return "t_reset_password"
+}
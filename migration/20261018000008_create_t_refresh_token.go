@@ -0,0 +1,36 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(Migration{
+		Version: "20261018000008",
+		Name:    "create_t_refresh_token",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&tRefreshToken20261018000008{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("t_refresh_token")
+		},
+	})
+}
+
+type tRefreshToken20261018000008 struct {
+	Id        uint   `gorm:"primaryKey;autoIncrement"`
+	UserId    uint   `gorm:"index"`
+	Email     string `gorm:"size:100"`
+	FamilyId  string `gorm:"size:64;index"`
+	TokenHash string `gorm:"size:64;uniqueIndex"`
+	ExpiredOn *time.Time
+	UsedOn    *time.Time
+	RevokedOn *time.Time
+	CreatedOn *time.Time
+}
+
+func (tRefreshToken20261018000008) TableName() string {
+	return "t_refresh_token"
+}
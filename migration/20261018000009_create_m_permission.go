@@ -0,0 +1,38 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(Migration{
+		Version: "20261018000009",
+		Name:    "create_m_permission",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&mPermission20261018000009{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("m_permission")
+		},
+	})
+}
+
+type mPermission20261018000009 struct {
+	Id   uint   `gorm:"primaryKey;autoIncrement"`
+	Code string `gorm:"size:50;uniqueIndex"`
+	Name string `gorm:"size:100"`
+
+	CreatedBy  uint `gorm:"not null"`
+	CreatedOn  *time.Time
+	ModifiedBy uint
+	ModifiedOn *time.Time
+	DeletedBy  uint
+	DeletedOn  *time.Time
+	IsDelete   *bool `gorm:"default:false"`
+}
+
+func (mPermission20261018000009) TableName() string {
+	return "m_permission"
+}
@@ -0,0 +1,32 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(Migration{
+		Version: "20261018000010",
+		Name:    "create_m_role_permission",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&mRolePermission20261018000010{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("m_role_permission")
+		},
+	})
+}
+
+type mRolePermission20261018000010 struct {
+	RoleId       uint `gorm:"primaryKey;autoIncrement:false"`
+	PermissionId uint `gorm:"primaryKey;autoIncrement:false;index"`
+
+	CreatedBy uint `gorm:"not null"`
+	CreatedOn *time.Time
+}
+
+func (mRolePermission20261018000010) TableName() string {
+	return "m_role_permission"
+}
@@ -0,0 +1,78 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// permissions20261018000011 are the permissions required by the routes when
+// they were introduced. ROLE_ADMIN gets all of them, the other roles keep
+// their access to the notes only.
+var permissions20261018000011 = []struct {
+	code    string
+	name    string
+	anyRole bool
+}{
+	{"biodata:read", "Read biodata", false},
+	{"biodata:write", "Write biodata", false},
+	{"roles:read", "Read roles", false},
+	{"roles:write", "Write roles and their permissions", false},
+	{"permissions:read", "Read permissions", false},
+	{"permissions:write", "Write permissions", false},
+	{"users:read", "Read users", false},
+	{"users:write", "Write, unlock and revoke users", false},
+	{"notes:read", "Read notes", true},
+	{"notes:write", "Write notes", true},
+}
+
+func init() {
+	Register(Migration{
+		Version: "20261018000011",
+		Name:    "seed_m_permission",
+		Up: func(tx *gorm.DB) error {
+			now := time.Now()
+
+			var roles []mRole20240216000002
+			if err := tx.Where("is_delete IS NULL OR is_delete = ?", false).Find(&roles).Error; err != nil {
+				return err
+			}
+
+			for _, p := range permissions20261018000011 {
+				permission := mPermission20261018000009{Code: p.code, Name: p.name, CreatedOn: &now}
+				if err := tx.Create(&permission).Error; err != nil {
+					return err
+				}
+
+				for _, role := range roles {
+					if role.Code != "ROLE_ADMIN" && !p.anyRole {
+						continue
+					}
+					grant := mRolePermission20261018000010{RoleId: role.Id, PermissionId: permission.Id, CreatedOn: &now}
+					if err := tx.Create(&grant).Error; err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			codes := make([]string, 0, len(permissions20261018000011))
+			for _, p := range permissions20261018000011 {
+				codes = append(codes, p.code)
+			}
+
+			var ids []uint
+			if err := tx.Model(&mPermission20261018000009{}).Where("code IN ?", codes).Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				return nil
+			}
+			if err := tx.Where("permission_id IN ?", ids).Delete(&mRolePermission20261018000010{}).Error; err != nil {
+				return err
+			}
+			return tx.Where("id IN ?", ids).Delete(&mPermission20261018000009{}).Error
+		},
+	})
+}
@@ -0,0 +1,37 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(Migration{
+		Version: "20261018000012",
+		Name:    "create_t_note_share",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&tNoteShare20261018000012{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("t_note_share")
+		},
+	})
+}
+
+type tNoteShare20261018000012 struct {
+	Id         uint   `gorm:"primaryKey;autoIncrement"`
+	NoteId     uint   `gorm:"not null;uniqueIndex:idx_t_note_share_grantee"`
+	UserId     uint   `gorm:"uniqueIndex:idx_t_note_share_grantee;index"`
+	RoleId     uint   `gorm:"uniqueIndex:idx_t_note_share_grantee;index"`
+	Permission string `gorm:"size:10"`
+
+	CreatedBy  uint `gorm:"not null"`
+	CreatedOn  *time.Time
+	ModifiedBy uint
+	ModifiedOn *time.Time
+}
+
+func (tNoteShare20261018000012) TableName() string {
+	return "t_note_share"
+}
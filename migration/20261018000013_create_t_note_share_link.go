@@ -0,0 +1,38 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(Migration{
+		Version: "20261018000013",
+		Name:    "create_t_note_share_link",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&tNoteShareLink20261018000013{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("t_note_share_link")
+		},
+	})
+}
+
+type tNoteShareLink20261018000013 struct {
+	Id             uint   `gorm:"primaryKey;autoIncrement"`
+	NoteId         uint   `gorm:"not null;index"`
+	SlugHash       string `gorm:"size:64;uniqueIndex"`
+	PasswordHash   string `gorm:"size:255"`
+	ExpiredOn      *time.Time
+	RevokedOn      *time.Time
+	AccessCount    int64 `gorm:"not null;default:0"`
+	LastAccessedOn *time.Time
+
+	CreatedBy uint `gorm:"not null"`
+	CreatedOn *time.Time
+}
+
+func (tNoteShareLink20261018000013) TableName() string {
+	return "t_note_share_link"
+}
@@ -0,0 +1,59 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(Migration{
+		Version: "20261018000014",
+		Name:    "create_t_note_revision",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().CreateTable(&tNoteRevision20261018000014{}); err != nil {
+				return err
+			}
+
+			// the current text of a note is its first revision
+			var notes []mNotes20240216000006
+			return tx.FindInBatches(&notes, 100, func(batch *gorm.DB, _ int) error {
+				revisions := make([]tNoteRevision20261018000014, 0, len(notes))
+				for _, note := range notes {
+					createdBy, createdOn := note.CreatedBy, note.CreatedOn
+					if note.ModifiedBy != 0 {
+						createdBy, createdOn = note.ModifiedBy, note.ModifiedOn
+					}
+					revisions = append(revisions, tNoteRevision20261018000014{
+						NoteId:    note.Id,
+						Revision:  1,
+						Title:     note.Title,
+						Content:   note.Content,
+						CreatedBy: createdBy,
+						CreatedOn: createdOn,
+					})
+				}
+				return tx.Create(&revisions).Error
+			}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("t_note_revision")
+		},
+	})
+}
+
+type tNoteRevision20261018000014 struct {
+	Id           uint   `gorm:"primaryKey;autoIncrement"`
+	NoteId       uint   `gorm:"not null;uniqueIndex:idx_t_note_revision_note"`
+	Revision     uint   `gorm:"not null;uniqueIndex:idx_t_note_revision_note"`
+	Title        string `gorm:"size:200"`
+	Content      string `gorm:"type:text"`
+	RestoredFrom uint
+
+	CreatedBy uint `gorm:"not null"`
+	CreatedOn *time.Time
+}
+
+func (tNoteRevision20261018000014) TableName() string {
+	return "t_note_revision"
+}
@@ -0,0 +1,85 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// permissions20261018000015 replace the ROLE_ADMIN checks of the admin only
+// routes and switches.
+var permissions20261018000015 = []struct {
+	code string
+	name string
+}{
+	{"admin:tokens", "Read and write the reset tokens"},
+	{"admin:password_history", "Read and write the password history"},
+	{"notes:all_owners", "Reach the notes of every user"},
+	{"deleted:read", "Read soft-deleted data"},
+}
+
+// adminRole20261018000015 is the role granted every permission. A migration
+// adding permissions grants them to it too.
+const adminRole20261018000015 = "ROLE_ADMIN"
+
+func init() {
+	Register(Migration{
+		Version: "20261018000015",
+		Name:    "seed_admin_role",
+		Up: func(tx *gorm.DB) error {
+			now := time.Now()
+
+			for _, p := range permissions20261018000015 {
+				permission := mPermission20261018000009{Code: p.code, Name: p.name, CreatedOn: &now}
+				if err := tx.Create(&permission).Error; err != nil {
+					return err
+				}
+			}
+
+			// a fresh database has no role yet, its first admin gets this one
+			var role mRole20240216000002
+			result := tx.Where("code = ?", adminRole20261018000015).Limit(1).Find(&role)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				role = mRole20240216000002{Name: "Administrator", Code: adminRole20261018000015, Level: 0, CreatedOn: &now}
+				if err := tx.Create(&role).Error; err != nil {
+					return err
+				}
+			}
+
+			var permissionIds []uint
+			granted := tx.Model(&mRolePermission20261018000010{}).Select("permission_id").Where("role_id = ?", role.Id)
+			if err := tx.Model(&mPermission20261018000009{}).Where("id NOT IN (?)", granted).Pluck("id", &permissionIds).Error; err != nil {
+				return err
+			}
+			for _, permissionId := range permissionIds {
+				grant := mRolePermission20261018000010{RoleId: role.Id, PermissionId: permissionId, CreatedOn: &now}
+				if err := tx.Create(&grant).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		// the admin role stays, it may have been there before
+		Down: func(tx *gorm.DB) error {
+			codes := make([]string, 0, len(permissions20261018000015))
+			for _, p := range permissions20261018000015 {
+				codes = append(codes, p.code)
+			}
+
+			var ids []uint
+			if err := tx.Model(&mPermission20261018000009{}).Where("code IN ?", codes).Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				return nil
+			}
+			if err := tx.Where("permission_id IN ?", ids).Delete(&mRolePermission20261018000010{}).Error; err != nil {
+				return err
+			}
+			return tx.Where("id IN ?", ids).Delete(&mPermission20261018000009{}).Error
+		},
+	})
+}
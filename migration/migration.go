@@ -0,0 +1,262 @@
+// Package migration holds the versioned schema migrations of the database.
+//
+// Every migration lives in its own <version>_<name>.go file and registers
+// itself from init. A migration must not use the structs of the model
+// package: it declares a snapshot of the table as it was at that version so
+// later model changes do not rewrite old migrations.
+//
+// Up and Down run in a transaction together with the schema_migrations
+// bookkeeping. MySQL commits DDL statements implicitly, so keep one schema
+// change per migration there.
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/amsatrio/gin_notes/util"
+)
+
+type Migration struct {
+	// Version orders the migrations, it is the creation time formatted as
+	// 20060102150405.
+	Version string
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration is a row of the bookkeeping table, one per applied
+// migration.
+type SchemaMigration struct {
+	Version   string    `gorm:"primaryKey;size:14"`
+	Name      string    `gorm:"size:255;not null"`
+	AppliedOn time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+type Status struct {
+	Version   string
+	Name      string
+	Applied   bool
+	AppliedOn *time.Time
+	// Missing is set for an applied version that has no migration in this
+	// build.
+	Missing bool
+}
+
+const versionLayout = "20060102150405"
+
+var registry = map[string]Migration{}
+
+var nameFormat = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Register adds a migration. It panics on a duplicated or invalid version so
+// a broken build fails at startup.
+func Register(migration Migration) {
+	if _, err := time.Parse(versionLayout, migration.Version); err != nil {
+		panic("migration: invalid version " + migration.Version)
+	}
+	if migration.Up == nil || migration.Down == nil {
+		panic("migration: " + migration.Version + " needs both Up and Down")
+	}
+	if _, ok := registry[migration.Version]; ok {
+		panic("migration: duplicate version " + migration.Version)
+	}
+	registry[migration.Version] = migration
+}
+
+// Migrations returns every registered migration ordered by version.
+func Migrations() []Migration {
+	migrations := make([]Migration, 0, len(registry))
+	for _, migration := range registry {
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations
+}
+
+// Up applies the pending migrations in order, at most steps of them when
+// steps is greater than 0. It returns the applied migrations.
+func Up(db *gorm.DB, steps int) ([]Migration, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range Migrations() {
+		if steps > 0 && len(done) >= steps {
+			break
+		}
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		util.Log("INFO", "migration", "Up", migration.Version+" "+migration.Name)
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedOn: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %s %s: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down reverts the last applied migrations, steps of them or one when steps
+// is 0 or less. It returns the reverted migrations.
+func Down(db *gorm.DB, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(versions)))
+
+	var done []Migration
+	for _, version := range versions {
+		if len(done) >= steps {
+			break
+		}
+		migration, ok := registry[version]
+		if !ok {
+			return done, errors.New("migration " + version + " is applied but missing in this build")
+		}
+
+		util.Log("INFO", "migration", "Down", migration.Version+" "+migration.Name)
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{}, "version = ?", migration.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %s %s: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// GetStatus lists every registered migration and every applied version
+// ordered by version.
+func GetStatus(db *gorm.DB) ([]Status, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+	for _, migration := range Migrations() {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedOn = &row.AppliedOn
+		}
+		statuses = append(statuses, status)
+	}
+	for version, row := range applied {
+		if _, ok := registry[version]; ok {
+			continue
+		}
+		statuses = append(statuses, Status{
+			Version:   version,
+			Name:      row.Name,
+			Applied:   true,
+			AppliedOn: &row.AppliedOn,
+			Missing:   true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// Create writes an empty migration named name into dir and returns its path.
+func Create(dir string, name string) (string, error) {
+	if !nameFormat.MatchString(name) {
+		return "", errors.New("migration name must be snake_case, e.g. add_m_notes_tag")
+	}
+
+	version := time.Now().Format(versionLayout)
+	path := filepath.Join(dir, version+"_"+name+".go")
+	source := fmt.Sprintf(`package migration
+
+import "gorm.io/gorm"
+
+func init() {
+	Register(Migration{
+		Version: %q,
+		Name:    %q,
+		Up: func(tx *gorm.DB) error {
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			return nil
+		},
+	})
+}
+`, version, name)
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(source); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// appliedVersions creates the bookkeeping table when needed and returns the
+// applied migrations by version.
+func appliedVersions(db *gorm.DB) (map[string]SchemaMigration, error) {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, err
+	}
+
+	var rows []SchemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[string]SchemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
@@ -0,0 +1,20 @@
+package model
+
+import "github.com/amsatrio/gin_notes/model/response"
+
+type MPermission struct {
+	Id         uint              `form:"id" json:"id" xml:"id" gorm:"primary_key;not null;comment:Auto increment" binding:"required"`
+	Code       string            `form:"code" json:"code" xml:"code" gorm:"size:50;uniqueIndex" binding:"required,max=50"`
+	Name       string            `form:"name" json:"name" xml:"name" gorm:"size:100" binding:"max=100"`
+	CreatedBy  uint              `form:"createdBy" json:"createdBy" xml:"createdBy" gorm:"not null"`
+	CreatedOn  response.JSONTime `form:"createdOn" json:"createdOn" xml:"createdOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+	ModifiedBy uint              `form:"modifiedBy" json:"modifiedBy" xml:"modifiedBy"`
+	ModifiedOn response.JSONTime `form:"modifiedOn" json:"modifiedOn" xml:"modifiedOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+	DeletedBy  uint              `form:"deletedBy" json:"deletedBy" xml:"deletedBy"`
+	DeletedOn  response.JSONTime `form:"deletedOn" json:"deletedOn" xml:"deletedOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+	IsDelete   *bool             `form:"isDelete" json:"isDelete" xml:"isDelete" gorm:"default:false;comment:default FALSE"`
+}
+
+func (MPermission) TableName() string {
+	return "m_permission"
+}
@@ -0,0 +1,15 @@
+package model
+
+import "github.com/amsatrio/gin_notes/model/response"
+
+// MRolePermission grants the permission PermissionId to the role RoleId.
+type MRolePermission struct {
+	RoleId       uint              `form:"roleId" json:"roleId" xml:"roleId" gorm:"primaryKey;autoIncrement:false"`
+	PermissionId uint              `form:"permissionId" json:"permissionId" xml:"permissionId" gorm:"primaryKey;autoIncrement:false;index"`
+	CreatedBy    uint              `form:"createdBy" json:"createdBy" xml:"createdBy" gorm:"not null"`
+	CreatedOn    response.JSONTime `form:"createdOn" json:"createdOn" xml:"createdOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+}
+
+func (MRolePermission) TableName() string {
+	return "m_role_permission"
+}
@@ -1,23 +1,29 @@
 package model
 
-import "github.com/amsatrio/gin_notes/model/response"
+import (
+	"encoding/json"
+	"encoding/xml"
+
+	"github.com/amsatrio/gin_notes/model/response"
+)
 
 type MUser struct {
-	Id           uint              `form:"id" json:"id" xml:"id" gorm:"primary_key;not null;type:bigint;comment:Auto increment" binding:"required"`
-	BiodataId    uint              `form:"biodataId" json:"biodataId" xml:"biodataId" gorm:"type:bigint"`
-	RoleId       uint              `form:"roleId" json:"roleId" xml:"roleId" gorm:"type:bigint"`
-	Email        string            `form:"email" json:"email" xml:"email" gorm:"size:100;type:varchar(100)" binding:"max=100"`
-	Password     string            `form:"password" json:"password" xml:"password" gorm:"size:255;type:varchar(255)" binding:"max=255"`
-	LoginAttempt int               `form:"loginAttempt" json:"loginAttempt" xml:"loginAttempt" gorm:"type:int"`
-	IsLocked     bool              `form:"isLocked" json:"isLocked" xml:"isLocked" gorm:"type:boolean"`
-	LastLogin    response.JSONTime `form:"lastLogin" json:"lastLogin" xml:"lastLogin" gorm:"type:datetime" swaggertype:"string" example:"2024-02-16 10:33:10"`
-	CreatedBy    uint              `form:"createdBy" json:"createdBy" xml:"createdBy" gorm:"not null;type:bigint"`
-	CreatedOn    response.JSONTime `form:"createdOn" json:"createdOn" xml:"createdOn" gorm:"type:datetime" swaggertype:"string" example:"2024-02-16 10:33:10"`
-	ModifiedBy   uint              `form:"modifiedBy" json:"modifiedBy" xml:"modifiedBy" gorm:"type:bigint"`
-	ModifiedOn   response.JSONTime `form:"modifiedOn" json:"modifiedOn" xml:"modifiedOn" gorm:"type:datetime" swaggertype:"string" example:"2024-02-16 10:33:10"`
-	DeletedBy    uint              `form:"deletedBy" json:"deletedBy" xml:"deletedBy" gorm:"type:bigint"`
-	DeletedOn    response.JSONTime `form:"deletedOn" json:"deletedOn" xml:"deletedOn" gorm:"type:datetime" swaggertype:"string" example:"2024-02-16 10:33:10"`
-	IsDelete     *bool             `form:"isDelete" json:"isDelete" xml:"isDelete" gorm:"type:boolean;comment:default FALSE"`
+	Id           uint              `form:"id" json:"id" xml:"id" gorm:"primary_key;not null;comment:Auto increment" binding:"required"`
+	BiodataId    uint              `form:"biodataId" json:"biodataId" xml:"biodataId"`
+	RoleId       uint              `form:"roleId" json:"roleId" xml:"roleId"`
+	Email        string            `form:"email" json:"email" xml:"email" gorm:"size:100" binding:"max=100"`
+	Password     string            `form:"password" json:"password" xml:"password" gorm:"size:255" binding:"max=255"`
+	LoginAttempt int               `form:"loginAttempt" json:"loginAttempt" xml:"loginAttempt"`
+	IsLocked     bool              `form:"isLocked" json:"isLocked" xml:"isLocked"`
+	LastLogin    response.JSONTime `form:"lastLogin" json:"lastLogin" xml:"lastLogin" swaggertype:"string" example:"2024-02-16 10:33:10"`
+	LockedOn     response.JSONTime `form:"lockedOn" json:"lockedOn" xml:"lockedOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+	CreatedBy    uint              `form:"createdBy" json:"createdBy" xml:"createdBy" gorm:"not null"`
+	CreatedOn    response.JSONTime `form:"createdOn" json:"createdOn" xml:"createdOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+	ModifiedBy   uint              `form:"modifiedBy" json:"modifiedBy" xml:"modifiedBy"`
+	ModifiedOn   response.JSONTime `form:"modifiedOn" json:"modifiedOn" xml:"modifiedOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+	DeletedBy    uint              `form:"deletedBy" json:"deletedBy" xml:"deletedBy"`
+	DeletedOn    response.JSONTime `form:"deletedOn" json:"deletedOn" xml:"deletedOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+	IsDelete     *bool             `form:"isDelete" json:"isDelete" xml:"isDelete" gorm:"default:false;comment:default FALSE"`
 
 	MBiodata MBiodata `gorm:"foreignKey:BiodataId"`
 	MRole    MRole    `gorm:"foreignKey:RoleId"`
@@ -26,3 +32,25 @@ type MUser struct {
 func (MUser) TableName() string {
 	return "m_user"
 }
+
+func (MUser) QueryExcludedFields() []string {
+	return []string{"password"}
+}
+
+// mUserJSON is MUser without the Password in responses. The password is still
+// read from requests.
+type mUserJSON MUser
+
+func (m MUser) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		mUserJSON
+		Password string `json:"password,omitempty"`
+	}{mUserJSON: mUserJSON(m)})
+}
+
+func (m MUser) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(struct {
+		mUserJSON
+		Password string `xml:"password,omitempty"`
+	}{mUserJSON: mUserJSON(m)}, start)
+}
@@ -0,0 +1,13 @@
+package request
+
+type CountMode string
+
+const (
+	COUNT_EXACT    CountMode = "EXACT"
+	COUNT_ESTIMATE CountMode = "ESTIMATE"
+	COUNT_NONE     CountMode = "NONE"
+)
+
+func (c CountMode) String() string {
+	return string(c)
+}
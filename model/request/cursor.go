@@ -0,0 +1,10 @@
+package request
+
+// Cursor switches GetPage* to keyset pagination. After / Before hold the
+// opaque cursor returned in response.Page.
+type Cursor struct {
+	Enabled bool
+	After   string
+	Before  string
+	Count   CountMode
+}
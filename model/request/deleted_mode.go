@@ -0,0 +1,14 @@
+package request
+
+// DeletedMode selects how soft-deleted rows are returned.
+type DeletedMode string
+
+const (
+	DELETED_EXCLUDE DeletedMode = "EXCLUDE"
+	DELETED_INCLUDE DeletedMode = "INCLUDE"
+	DELETED_ONLY    DeletedMode = "ONLY"
+)
+
+func (d DeletedMode) String() string {
+	return string(d)
+}
@@ -1,9 +1,53 @@
 package request
 
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Filter is either a single condition (Id, Value, MatchMode, DataType) or a
+// group of conditions when Filters is not empty.
+//
+// Mode has one meaning per kind of filter:
+//   - on a single condition it joins the condition with the ones before it
+//   - on a group it joins the Filters of the group, the group itself is
+//     joined with the Mode of the group around it, AND at the top
+//
+// A single condition without Mode is joined with the Mode of its group.
 type Filter struct {
 	Id        string          `json:"id" example:"fullName"`
 	Value     interface{}     `json:"value" example:"Adi"`
 	MatchMode FilterMatchMode `json:"matchMode" example:"CONTAINS"`
 	DataType  FilterDataType  `json:"dataType" example:"TEXT"`
-	Mode      FilterMode      `json:"mode" example:"AND"`
+	// Mode is AND or OR, the join with the previous conditions for a single
+	// condition and the join of the Filters for a group
+	Mode    FilterMode `json:"mode" example:"AND"`
+	Filters []Filter   `json:"filters,omitempty"`
+}
+
+func (f Filter) IsGroup() bool {
+	return len(f.Filters) > 0
+}
+
+// Filters accepts both the flat `[{...}, {...}]` format and a single group
+// object `{"mode":"OR","filters":[...]}` for the _filter query.
+type Filters []Filter
+
+func (f *Filters) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var group Filter
+		if err := json.Unmarshal(data, &group); err != nil {
+			return err
+		}
+		*f = Filters{group}
+		return nil
+	}
+
+	var filters []Filter
+	if err := json.Unmarshal(data, &filters); err != nil {
+		return err
+	}
+	*f = filters
+	return nil
 }
@@ -0,0 +1,5 @@
+package request
+
+type RequestForgotPassword struct {
+	Email string `form:"email" json:"email" xml:"email" binding:"required,max=100,email"`
+}
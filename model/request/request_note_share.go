@@ -0,0 +1,9 @@
+package request
+
+// RequestNoteShare grants Permission to the user UserId or to the role
+// RoleId, exactly one of them is set.
+type RequestNoteShare struct {
+	UserId     uint   `form:"userId" json:"userId" xml:"userId"`
+	RoleId     uint   `form:"roleId" json:"roleId" xml:"roleId"`
+	Permission string `form:"permission" json:"permission" xml:"permission" binding:"required,oneof=view edit owner"`
+}
@@ -0,0 +1,10 @@
+package request
+
+import "github.com/amsatrio/gin_notes/model/response"
+
+// RequestNoteShareLink creates a link expiring on ExpiredOn, never when it is
+// not set, and asking for Password when it is set.
+type RequestNoteShareLink struct {
+	ExpiredOn response.JSONTime `form:"expiredOn" json:"expiredOn" xml:"expiredOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+	Password  string            `form:"password" json:"password" xml:"password" binding:"max=72"`
+}
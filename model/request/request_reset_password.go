@@ -0,0 +1,6 @@
+package request
+
+type RequestResetPassword struct {
+	Token    string `form:"token" json:"token" xml:"token" binding:"required,max=64"`
+	Password string `form:"password" json:"password" xml:"password" binding:"required,min=2,max=64"`
+}
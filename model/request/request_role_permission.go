@@ -0,0 +1,5 @@
+package request
+
+type RequestRolePermission struct {
+	PermissionIds []uint `form:"permissionIds" json:"permissionIds" xml:"permissionIds" binding:"required"`
+}
@@ -8,19 +8,22 @@ import (
 	"time"
 )
 
+// JSONTimeLayout is the layout used for every JSONTime on the wire.
+const JSONTimeLayout = "2006-01-02 15:04:05"
+
 type JSONTime struct {
 	time.Time
 }
 
 func (jt JSONTime) MarshalJSON() ([]byte, error) {
-	formatted := jt.Format("2006-01-02 15:04:05")
+	formatted := jt.Format(JSONTimeLayout)
 	return []byte(`"` + formatted + `"`), nil
 }
 
 func (jt *JSONTime) UnmarshalJSON(data []byte) error {
 	str := strings.Trim(string(data), `"`)
 
-	parsedTime, err := time.Parse("2006-01-02 15:04:05", str)
+	parsedTime, err := time.Parse(JSONTimeLayout, str)
 	if err != nil {
 		return err
 	}
@@ -41,15 +44,48 @@ func (jt *JSONTime) Scan(value interface{}) error {
 	case time.Time:
 		*jt = JSONTime{Time: st}
 	case []byte:
-		return json.Unmarshal(st, jt)
+		return jt.scanString(string(st))
 	case string:
-		return json.Unmarshal([]byte(st), jt)
+		return jt.scanString(st)
 	default:
 		return errors.New("unsupported type for JSONTime")
 	}
 	return nil
 }
 
+// scanLayouts are the text formats a driver can return a time column in,
+// e.g. sqlite stores "2006-01-02 15:04:05.999999999-07:00".
+var scanLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	JSONTimeLayout,
+	time.DateOnly,
+}
+
+func (jt *JSONTime) scanString(value string) error {
+	if strings.HasPrefix(value, `"`) {
+		return json.Unmarshal([]byte(value), jt)
+	}
+	if value == "" {
+		*jt = JSONTime{}
+		return nil
+	}
+	for _, layout := range scanLayouts {
+		if parsedTime, err := time.Parse(layout, value); err == nil {
+			*jt = JSONTime{Time: parsedTime}
+			return nil
+		}
+	}
+	return errors.New("unsupported time format for JSONTime: " + value)
+}
+
+// GormDataType lets gorm create the column with the time type of each
+// dialect.
+func (JSONTime) GormDataType() string {
+	return "time"
+}
+
 func (jt JSONTime) Value() (driver.Value, error) {
 	if jt.IsZero() {
 		return nil, nil
@@ -12,4 +12,6 @@ type Page struct {
 	Last             bool        `json:"last" example:"false"`
 	First            bool        `json:"first" example:"true"`
 	Empty            bool        `json:"empty" example:"false"`
+	NextCursor       string      `json:"nextCursor,omitempty" example:"eyJzIjoiaWQ6YXNjIiwidiI6WzVdfQ"`
+	PrevCursor       string      `json:"prevCursor,omitempty" example:"eyJzIjoiaWQ6YXNjIiwidiI6WzFdfQ"`
 }
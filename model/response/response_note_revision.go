@@ -0,0 +1,21 @@
+package response
+
+// DiffLine is a line of a diff, Op is "equal", "insert" or "delete". OldLine
+// and NewLine are the 1-based numbers of the line in the old and the new
+// text, 0 when it is not in that text.
+type DiffLine struct {
+	Op      string `json:"op" example:"insert"`
+	OldLine int    `json:"oldLine" example:"0"`
+	NewLine int    `json:"newLine" example:"3"`
+	Text    string `json:"text" example:"text"`
+}
+
+// ResponseNoteRevisionDiff is the line diff from the revision From of a note
+// to the revision To.
+type ResponseNoteRevisionDiff struct {
+	NoteId  uint       `json:"noteId" example:"1"`
+	From    uint       `json:"from" example:"1"`
+	To      uint       `json:"to" example:"2"`
+	Title   []DiffLine `json:"title"`
+	Content []DiffLine `json:"content"`
+}
@@ -0,0 +1,18 @@
+package response
+
+// ResponseNoteShareLink is a created share link, its Slug is only returned
+// once.
+type ResponseNoteShareLink struct {
+	Id        uint     `json:"id" example:"1"`
+	Slug      string   `json:"slug" example:"slug"`
+	Path      string   `json:"path" example:"/p/slug"`
+	ExpiredOn JSONTime `json:"expiredOn" example:"2024-02-16 10:33:10" swaggertype:"string"`
+}
+
+// ResponseSharedNote is the read-only note served by a share link.
+type ResponseSharedNote struct {
+	Title      string   `json:"title" example:"title"`
+	Content    string   `json:"content" example:"content"`
+	CreatedOn  JSONTime `json:"createdOn" example:"2024-02-16 10:33:10" swaggertype:"string"`
+	ModifiedOn JSONTime `json:"modifiedOn" example:"2024-02-16 10:33:10" swaggertype:"string"`
+}
@@ -0,0 +1,6 @@
+package response
+
+type ResponseRoleAuthorities struct {
+	Authorities []string `json:"authorities" example:"ROLE_ADMIN,ROLE_USER"`
+	Permissions []string `json:"permissions" example:"notes:read,notes:write"`
+}
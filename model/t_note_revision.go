@@ -0,0 +1,21 @@
+package model
+
+import "github.com/amsatrio/gin_notes/model/response"
+
+// TNoteRevision is the Title and Content of the note NoteId as saved by
+// CreatedBy, numbered from 1 per note. A row is never updated. RestoredFrom
+// is the revision it restores, 0 for a plain save.
+type TNoteRevision struct {
+	Id           uint              `form:"id" json:"id" xml:"id" gorm:"primary_key;not null;comment:Auto increment"`
+	NoteId       uint              `form:"noteId" json:"noteId" xml:"noteId" gorm:"not null;uniqueIndex:idx_t_note_revision_note"`
+	Revision     uint              `form:"revision" json:"revision" xml:"revision" gorm:"not null;uniqueIndex:idx_t_note_revision_note"`
+	Title        string            `form:"title" json:"title" xml:"title" gorm:"size:200"`
+	Content      string            `form:"content" json:"content" xml:"content" gorm:"type:text"`
+	RestoredFrom uint              `form:"restoredFrom" json:"restoredFrom" xml:"restoredFrom"`
+	CreatedBy    uint              `form:"createdBy" json:"createdBy" xml:"createdBy" gorm:"not null"`
+	CreatedOn    response.JSONTime `form:"createdOn" json:"createdOn" xml:"createdOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+}
+
+func (TNoteRevision) TableName() string {
+	return "t_note_revision"
+}
@@ -0,0 +1,22 @@
+package model
+
+import "github.com/amsatrio/gin_notes/model/response"
+
+// TNoteShare grants Permission on the note NoteId to the user UserId or to
+// every user of the role RoleId, the other id is 0. Permission is one of
+// view, edit and owner, each granting the ones before it.
+type TNoteShare struct {
+	Id         uint              `form:"id" json:"id" xml:"id" gorm:"primary_key;not null;comment:Auto increment"`
+	NoteId     uint              `form:"noteId" json:"noteId" xml:"noteId" gorm:"not null;uniqueIndex:idx_t_note_share_grantee"`
+	UserId     uint              `form:"userId" json:"userId" xml:"userId" gorm:"uniqueIndex:idx_t_note_share_grantee;index"`
+	RoleId     uint              `form:"roleId" json:"roleId" xml:"roleId" gorm:"uniqueIndex:idx_t_note_share_grantee;index"`
+	Permission string            `form:"permission" json:"permission" xml:"permission" gorm:"size:10"`
+	CreatedBy  uint              `form:"createdBy" json:"createdBy" xml:"createdBy" gorm:"not null"`
+	CreatedOn  response.JSONTime `form:"createdOn" json:"createdOn" xml:"createdOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+	ModifiedBy uint              `form:"modifiedBy" json:"modifiedBy" xml:"modifiedBy"`
+	ModifiedOn response.JSONTime `form:"modifiedOn" json:"modifiedOn" xml:"modifiedOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+}
+
+func (TNoteShare) TableName() string {
+	return "t_note_share"
+}
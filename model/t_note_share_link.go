@@ -0,0 +1,28 @@
+package model
+
+import "github.com/amsatrio/gin_notes/model/response"
+
+// TNoteShareLink publishes the note NoteId read-only to anyone with its slug.
+// Only the sha256 of the slug and the hash of the optional password are
+// stored. A zero ExpiredOn never expires.
+type TNoteShareLink struct {
+	Id             uint              `form:"id" json:"id" xml:"id" gorm:"primary_key;not null;comment:Auto increment"`
+	NoteId         uint              `form:"noteId" json:"noteId" xml:"noteId" gorm:"not null;index"`
+	SlugHash       string            `form:"-" json:"-" xml:"-" gorm:"size:64;uniqueIndex"`
+	PasswordHash   string            `form:"-" json:"-" xml:"-" gorm:"size:255"`
+	HasPassword    bool              `form:"hasPassword" json:"hasPassword" xml:"hasPassword" gorm:"-"`
+	ExpiredOn      response.JSONTime `form:"expiredOn" json:"expiredOn" xml:"expiredOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+	RevokedOn      response.JSONTime `form:"revokedOn" json:"revokedOn" xml:"revokedOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+	AccessCount    int64             `form:"accessCount" json:"accessCount" xml:"accessCount"`
+	LastAccessedOn response.JSONTime `form:"lastAccessedOn" json:"lastAccessedOn" xml:"lastAccessedOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+	CreatedBy      uint              `form:"createdBy" json:"createdBy" xml:"createdBy" gorm:"not null"`
+	CreatedOn      response.JSONTime `form:"createdOn" json:"createdOn" xml:"createdOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+}
+
+func (TNoteShareLink) TableName() string {
+	return "t_note_share_link"
+}
+
+func (TNoteShareLink) QueryExcludedFields() []string {
+	return []string{"SlugHash", "PasswordHash"}
+}
@@ -0,0 +1,26 @@
+package model
+
+import "github.com/amsatrio/gin_notes/model/response"
+
+// TRefreshToken is an issued refresh token. Only the sha256 of the token is
+// stored. The tokens rotated from one login share a FamilyId, which is also
+// the sid claim of their access tokens.
+type TRefreshToken struct {
+	Id        uint              `form:"id" json:"id" xml:"id" gorm:"primary_key;not null;comment:Auto increment"`
+	UserId    uint              `form:"userId" json:"userId" xml:"userId" gorm:"index"`
+	Email     string            `form:"email" json:"email" xml:"email" gorm:"size:100"`
+	FamilyId  string            `form:"familyId" json:"familyId" xml:"familyId" gorm:"size:64;index"`
+	TokenHash string            `form:"-" json:"-" xml:"-" gorm:"size:64;uniqueIndex"`
+	ExpiredOn response.JSONTime `form:"expiredOn" json:"expiredOn" xml:"expiredOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+	UsedOn    response.JSONTime `form:"usedOn" json:"usedOn" xml:"usedOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+	RevokedOn response.JSONTime `form:"revokedOn" json:"revokedOn" xml:"revokedOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+	CreatedOn response.JSONTime `form:"createdOn" json:"createdOn" xml:"createdOn" swaggertype:"string" example:"2024-02-16 10:33:10"`
+}
+
+func (TRefreshToken) TableName() string {
+	return "t_refresh_token"
+}
+
+func (TRefreshToken) QueryExcludedFields() []string {
+	return []string{"TokenHash"}
+}
@@ -0,0 +1,594 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"github.com/amsatrio/gin_notes/constant"
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/model/request"
+	"github.com/amsatrio/gin_notes/model/response"
+	"github.com/amsatrio/gin_notes/util"
+)
+
+var schemaCache = &sync.Map{}
+
+type AuditAction string
+
+const (
+	AUDIT_CREATE  AuditAction = "CREATE"
+	AUDIT_UPDATE  AuditAction = "UPDATE"
+	AUDIT_DELETE  AuditAction = "DELETE"
+	AUDIT_RESTORE AuditAction = "RESTORE"
+)
+
+// AccessAction is what an Owned service is asked to do on a row.
+type AccessAction string
+
+const (
+	ACCESS_READ   AccessAction = "READ"
+	ACCESS_UPDATE AccessAction = "UPDATE"
+	ACCESS_DELETE AccessAction = "DELETE"
+	ACCESS_SHARE  AccessAction = "SHARE"
+)
+
+// CrudConfig holds what differs between two entities of a CrudService.
+type CrudConfig[T any] struct {
+	// UpdatableFields are the go field names copied from the request on
+	// update, e.g. []string{"Title", "Content"}.
+	UpdatableFields []string
+
+	// Validate runs on create and update before anything is saved.
+	Validate func(context context.Context, data *T, mUserAccess *model.MUser) error
+
+	// Prepare runs after Validate to derive stored values from the request,
+	// e.g. hash a password. old is nil on create and the saved row on update.
+	Prepare func(context context.Context, data *T, old *T) error
+
+	// Saved runs in the transaction of a create or update once data is
+	// saved. old is nil on create and the row before the update otherwise.
+	Saved func(context context.Context, tx *gorm.DB, data *T, old *T) error
+
+	// Committed runs once the transaction of a create or update is
+	// committed, for the side effects a rollback can not undo. Its error is
+	// returned although data stays saved.
+	Committed func(context context.Context, data *T, old *T) error
+
+	// Deleted runs in the transaction of a Delete or a Purge once the rows
+	// ids are deleted.
+	Deleted func(context context.Context, tx *gorm.DB, ids []uint) error
+
+	// Audit fills the audit fields. It defaults to SetAuditFields.
+	Audit func(context context.Context, data *T, action AuditAction, mUserAccess *model.MUser)
+
+	// OwnerField is the go field name of the id of the user owning a row,
+	// e.g. "CreatedBy". It is required by Owned.
+	OwnerField string
+
+	// Shared reports whether mUserAccess may do action on data it does not
+	// own. Without it only the owner reaches a row of an Owned service.
+	Shared func(context context.Context, db *gorm.DB, data *T, action AccessAction, mUserAccess *model.MUser) (bool, error)
+
+	// SharedScope scopes db to the rows shared with mUserAccess, it is
+	// required by SharedWith.
+	SharedScope func(db *gorm.DB, mUserAccess *model.MUser) *gorm.DB
+}
+
+// CrudService implements Get/Create/Update/Delete/SoftDelete/GetPage for any
+// model with an id and the Created*/Modified*/Deleted*/IsDelete audit fields.
+// Soft-deleted rows are hidden from Get, Update, SoftDelete and GetPage
+// unless a DeletedMode asks for them.
+type CrudService[T any] interface {
+	// Owned returns the service restricted to the rows of mUserAccess: GetPage
+	// skips the other rows and the other operations fail with
+	// constant.ErrorPermissionDenied on them unless CrudConfig.Shared grants
+	// them. It returns the service as is when CrudConfig.OwnerField is empty.
+	Owned(mUserAccess *model.MUser) CrudService[T]
+	// SharedWith is Owned, except that GetPage returns the rows other users
+	// shared with mUserAccess.
+	SharedWith(mUserAccess *model.MUser) CrudService[T]
+	// Authorize returns the row of id when the service may do action on it.
+	// It fails with gorm.ErrRecordNotFound when there is no such row and with
+	// constant.ErrorPermissionDenied when action is not allowed on it.
+	Authorize(context context.Context, id uint, action AccessAction) (*T, error)
+	Get(context context.Context, id uint) (*T, error)
+	Find(context context.Context, id uint, deletedRequest request.DeletedMode) (*T, error)
+	Create(context context.Context, data *T, mUserAccess *model.MUser) error
+	Update(context context.Context, data *T, mUserAccess *model.MUser) error
+	Delete(context context.Context, id uint, mUserAccess *model.MUser) error
+	SoftDelete(context context.Context, id uint, mUserAccess *model.MUser) error
+	Restore(context context.Context, id uint, mUserAccess *model.MUser) error
+	Purge(context context.Context, deletedBefore time.Time) (int64, error)
+	GetPage(
+		context context.Context,
+		sortRequest []request.Sort,
+		filterRequest []request.Filter,
+		searchRequest string,
+		pageInt int,
+		sizeInt int,
+		cursorRequest request.Cursor,
+		deletedRequest request.DeletedMode) (*response.Page, error)
+}
+
+type CrudServiceImpl[T any] struct {
+	db     *gorm.DB
+	config CrudConfig[T]
+
+	// access is the user of an Owned service, nil for every row
+	access *model.MUser
+	// shared makes GetPage return the rows shared with access
+	shared bool
+}
+
+func NewCrudService[T any](db *gorm.DB, config CrudConfig[T]) CrudService[T] {
+	if config.Audit == nil {
+		config.Audit = SetAuditFields[T]
+	}
+	return &CrudServiceImpl[T]{
+		db:     db,
+		config: config,
+	}
+}
+
+// SetAuditFields sets the Created*, Modified* or Deleted* fields of data
+// depending on action. Fields missing from the model are skipped.
+func SetAuditFields[T any](context context.Context, data *T, action AuditAction, mUserAccess *model.MUser) {
+	var userId uint
+	if mUserAccess != nil {
+		userId = mUserAccess.Id
+	}
+	now := response.JSONTime{Time: time.Now()}
+
+	switch action {
+	case AUDIT_CREATE:
+		setField(context, data, "CreatedBy", userId)
+		setField(context, data, "CreatedOn", now)
+	case AUDIT_UPDATE:
+		setField(context, data, "ModifiedBy", userId)
+		setField(context, data, "ModifiedOn", now)
+	case AUDIT_DELETE:
+		isDelete := true
+		setField(context, data, "DeletedBy", userId)
+		setField(context, data, "DeletedOn", now)
+		setField(context, data, "IsDelete", &isDelete)
+	case AUDIT_RESTORE:
+		isDelete := false
+		setField(context, data, "DeletedBy", uint(0))
+		setField(context, data, "DeletedOn", response.JSONTime{})
+		setField(context, data, "IsDelete", &isDelete)
+		setField(context, data, "ModifiedBy", userId)
+		setField(context, data, "ModifiedOn", now)
+	}
+}
+
+func setField(context context.Context, data interface{}, name string, value interface{}) {
+	sch, err := schema.Parse(data, schemaCache, schema.NamingStrategy{})
+	if err != nil {
+		util.LogError("service", "setField", "parse schema failed", err)
+		return
+	}
+	field := sch.LookUpField(name)
+	if field == nil {
+		return
+	}
+	if err := field.Set(context, reflect.ValueOf(data).Elem(), value); err != nil {
+		util.LogError("service", "setField", "set "+name+" failed", err)
+	}
+}
+
+func (s *CrudServiceImpl[T]) Owned(mUserAccess *model.MUser) CrudService[T] {
+	if s.config.OwnerField == "" {
+		return s
+	}
+	owned := *s
+	owned.access = mUserAccess
+	owned.shared = false
+	return &owned
+}
+
+func (s *CrudServiceImpl[T]) SharedWith(mUserAccess *model.MUser) CrudService[T] {
+	if s.config.OwnerField == "" {
+		return s
+	}
+	owned := *s
+	owned.access = mUserAccess
+	owned.shared = true
+	return &owned
+}
+
+func (s *CrudServiceImpl[T]) Authorize(context context.Context, id uint, action AccessAction) (*T, error) {
+	db, err := s.withDeleted(request.DELETED_EXCLUDE)
+	if err != nil {
+		return nil, err
+	}
+
+	var data T
+	result := db.First(&data, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if err := s.checkAccess(context, &data, action); err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// checkAccess returns constant.ErrorPermissionDenied when the user of an
+// Owned service may not do action on data.
+func (s *CrudServiceImpl[T]) checkAccess(context context.Context, data *T, action AccessAction) error {
+	if s.access == nil {
+		return nil
+	}
+	sch, err := schema.Parse(data, schemaCache, schema.NamingStrategy{})
+	if err != nil {
+		return err
+	}
+	field := sch.LookUpField(s.config.OwnerField)
+	if field == nil {
+		return errors.New("unknown owner field " + s.config.OwnerField)
+	}
+	value, _ := field.ValueOf(context, reflect.ValueOf(data).Elem())
+	if ownerId, ok := value.(uint); ok && ownerId == s.access.Id {
+		return nil
+	}
+
+	if s.config.Shared != nil {
+		shared, err := s.config.Shared(context, s.db, data, action, s.access)
+		if err != nil {
+			return err
+		}
+		if shared {
+			return nil
+		}
+	}
+	return constant.ErrorPermissionDenied
+}
+
+func (s *CrudServiceImpl[T]) Get(context context.Context, id uint) (*T, error) {
+	return s.Find(context, id, request.DELETED_EXCLUDE)
+}
+
+func (s *CrudServiceImpl[T]) Find(context context.Context, id uint, deletedRequest request.DeletedMode) (*T, error) {
+	db, err := s.withDeleted(deletedRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	var data T
+	result := db.First(&data, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if err := s.checkAccess(context, &data, ACCESS_READ); err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// withDeleted scopes s.db to the rows selected by deletedRequest.
+func (s *CrudServiceImpl[T]) withDeleted(deletedRequest request.DeletedMode) (*gorm.DB, error) {
+	resolver, err := util.NewColumnResolver(s.db, new(T))
+	if err != nil {
+		return nil, err
+	}
+	return util.ApplyDeletedFilter(s.db, deletedRequest, resolver), nil
+}
+
+func (s *CrudServiceImpl[T]) Create(context context.Context, data *T, mUserAccess *model.MUser) error {
+	if s.config.Validate != nil {
+		if err := s.config.Validate(context, data, mUserAccess); err != nil {
+			return err
+		}
+	}
+	if s.config.Prepare != nil {
+		if err := s.config.Prepare(context, data, nil); err != nil {
+			return err
+		}
+	}
+
+	s.config.Audit(context, data, AUDIT_CREATE, mUserAccess)
+
+	util.Log("INFO", "service", "CrudService", "Create: "+reflect.TypeOf(data).Elem().Name())
+
+	// find data
+	if id, ok := primaryKey(context, s.db, data); ok {
+		var old T
+		result := s.db.First(&old, id)
+		if result.Error == nil {
+			return errors.New("data exist")
+		}
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Omit(clause.Associations).Create(data)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if s.config.Saved != nil {
+			return s.config.Saved(context, tx, data, nil)
+		}
+		return nil
+	})
+	if err != nil || s.config.Committed == nil {
+		return err
+	}
+	return s.config.Committed(context, data, nil)
+}
+
+func (s *CrudServiceImpl[T]) Update(context context.Context, data *T, mUserAccess *model.MUser) error {
+	id, ok := primaryKey(context, s.db, data)
+	if !ok {
+		return errors.New("data not found")
+	}
+
+	db, err := s.withDeleted(request.DELETED_EXCLUDE)
+	if err != nil {
+		return err
+	}
+
+	var old T
+
+	// find data
+	result := db.First(&old, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return errors.New("data not found")
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if err := s.checkAccess(context, &old, ACCESS_UPDATE); err != nil {
+		return err
+	}
+
+	if s.config.Validate != nil {
+		if err := s.config.Validate(context, data, mUserAccess); err != nil {
+			return err
+		}
+	}
+	if s.config.Prepare != nil {
+		if err := s.config.Prepare(context, data, &old); err != nil {
+			return err
+		}
+	}
+
+	previous := old
+
+	// update data
+	stmt := &gorm.Statement{DB: s.db}
+	if err := stmt.Parse(&old); err != nil {
+		return err
+	}
+	oldValue := reflect.ValueOf(&old).Elem()
+	dataValue := reflect.ValueOf(data).Elem()
+	for _, name := range s.config.UpdatableFields {
+		field := stmt.Schema.LookUpField(name)
+		if field == nil {
+			return errors.New("unknown updatable field " + name)
+		}
+		value, _ := field.ValueOf(context, dataValue)
+		if err := field.Set(context, oldValue, value); err != nil {
+			return err
+		}
+	}
+	s.config.Audit(context, &old, AUDIT_UPDATE, mUserAccess)
+
+	// update data for response
+	*data = old
+
+	columns := append([]string{}, s.config.UpdatableFields...)
+	columns = append(columns, "ModifiedBy", "ModifiedOn")
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&old).Select(columns).Updates(&old)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if s.config.Saved != nil {
+			return s.config.Saved(context, tx, &old, &previous)
+		}
+		return nil
+	})
+	if err != nil || s.config.Committed == nil {
+		return err
+	}
+	return s.config.Committed(context, &old, &previous)
+}
+
+func (s *CrudServiceImpl[T]) Delete(context context.Context, id uint, mUserAccess *model.MUser) error {
+	var data T
+	if s.access != nil {
+		result := s.db.First(&data, id)
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return errors.New("data not found")
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		if err := s.checkAccess(context, &data, ACCESS_DELETE); err != nil {
+			return err
+		}
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&data, id)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("data not found")
+		}
+
+		if s.config.Deleted != nil {
+			return s.config.Deleted(context, tx, []uint{id})
+		}
+		return nil
+	})
+}
+
+func (s *CrudServiceImpl[T]) SoftDelete(context context.Context, id uint, mUserAccess *model.MUser) error {
+	return s.setDeleted(context, id, request.DELETED_EXCLUDE, AUDIT_DELETE, mUserAccess)
+}
+
+func (s *CrudServiceImpl[T]) Restore(context context.Context, id uint, mUserAccess *model.MUser) error {
+	return s.setDeleted(context, id, request.DELETED_ONLY, AUDIT_RESTORE, mUserAccess)
+}
+
+// setDeleted finds the row id among deletedRequest and updates the fields
+// changed by action.
+func (s *CrudServiceImpl[T]) setDeleted(context context.Context, id uint, deletedRequest request.DeletedMode, action AuditAction, mUserAccess *model.MUser) error {
+	db, err := s.withDeleted(deletedRequest)
+	if err != nil {
+		return err
+	}
+
+	var old T
+
+	// find data
+	result := db.First(&old, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return errors.New("data not found")
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if err := s.checkAccess(context, &old, ACCESS_DELETE); err != nil {
+		return err
+	}
+
+	// update data
+	s.config.Audit(context, &old, action, mUserAccess)
+
+	columns := []string{"DeletedBy", "DeletedOn", "IsDelete"}
+	if action == AUDIT_RESTORE {
+		columns = append(columns, "ModifiedBy", "ModifiedOn")
+	}
+	result = s.db.Model(&old).Select(columns).Updates(&old)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+// Purge hard-deletes the rows soft-deleted before deletedBefore. Models
+// without IsDelete and DeletedOn are skipped.
+func (s *CrudServiceImpl[T]) Purge(context context.Context, deletedBefore time.Time) (int64, error) {
+	resolver, err := util.NewColumnResolver(s.db, new(T))
+	if err != nil {
+		return 0, err
+	}
+	deletedOn, ok := resolver.Column("DeletedOn")
+	if !ok {
+		return 0, nil
+	}
+	if _, ok := resolver.Column("IsDelete"); !ok {
+		return 0, nil
+	}
+
+	var rowsAffected int64
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		db := util.ApplyDeletedFilter(tx, request.DELETED_ONLY, resolver).Where("? < ?", deletedOn, deletedBefore)
+		if s.config.Deleted == nil {
+			result := db.Delete(new(T))
+			rowsAffected = result.RowsAffected
+			return result.Error
+		}
+
+		// the ids are found first for Deleted
+		var ids []uint
+		if err := db.Model(new(T)).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		result := tx.Delete(new(T), ids)
+		if result.Error != nil {
+			return result.Error
+		}
+		rowsAffected = result.RowsAffected
+		return s.config.Deleted(context, tx, ids)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	util.Log("INFO", "service", "CrudService", "Purge: "+reflect.TypeOf(new(T)).Elem().Name()+" "+strconv.FormatInt(rowsAffected, 10)+" row(s)")
+	return rowsAffected, nil
+}
+
+func (s *CrudServiceImpl[T]) GetPage(
+	context context.Context,
+	sortRequest []request.Sort,
+	filterRequest []request.Filter,
+	searchRequest string,
+	pageInt int,
+	sizeInt int,
+	cursorRequest request.Cursor,
+	deletedRequest request.DeletedMode) (*response.Page, error) {
+
+	var rows []T
+	resolver, err := util.NewColumnResolver(s.db, new(T))
+	if err != nil {
+		return nil, err
+	}
+
+	util.Log("INFO", "service", "CrudService", "GetPage: "+reflect.TypeOf(rows).Elem().Name())
+
+	// Create a DB instance and build the base query
+	db := util.ApplyDeletedFilter(s.db, deletedRequest, resolver)
+
+	// keep the rows of the owner, or the ones shared with it
+	if s.access != nil {
+		owner, ok := resolver.Column(s.config.OwnerField)
+		if !ok {
+			return nil, errors.New("unknown owner field " + s.config.OwnerField)
+		}
+		if !s.shared {
+			db = db.Where("? = ?", owner, s.access.Id)
+		} else if s.config.SharedScope != nil {
+			db = s.config.SharedScope(db, s.access).Where("? <> ?", owner, s.access.Id)
+		} else {
+			db = db.Where("1 = 0")
+		}
+	}
+
+	// apply filtering
+	db = util.ApplyFiltering(db, filterRequest, resolver)
+
+	// apply global search
+	db = util.ApplyGlobalSearch(db, searchRequest, resolver)
+
+	// sort and paginate
+	page, err := util.Paginate(db, &rows, resolver, sortRequest, pageInt, sizeInt, cursorRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	util.Log("INFO", "service", "CrudService", "total elements: "+strconv.FormatInt(page.TotalElements, 10))
+
+	return page, nil
+}
+
+// primaryKey returns the primary key of data, false when it is not set.
+func primaryKey(context context.Context, db *gorm.DB, data interface{}) (interface{}, bool) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(data); err != nil || stmt.Schema.PrioritizedPrimaryField == nil {
+		return nil, false
+	}
+	value, isZero := stmt.Schema.PrioritizedPrimaryField.ValueOf(context, reflect.ValueOf(data).Elem())
+	return value, !isZero
+}
@@ -3,15 +3,22 @@ package service
 import (
 	"context"
 	"errors"
+	"os"
+	"strconv"
+	"time"
 
 	"gorm.io/gorm"
 
+	"github.com/amsatrio/gin_notes/constant"
 	"github.com/amsatrio/gin_notes/model"
 	"github.com/amsatrio/gin_notes/model/request"
+	"github.com/amsatrio/gin_notes/util"
 )
 
 type JwtService interface {
-	JwtAuthenticate(context context.Context, auth request.RequestAuth) ([]string, error)
+	// JwtAuthenticate checks the credentials of auth and returns the user
+	// with its MRole.
+	JwtAuthenticate(context context.Context, auth request.RequestAuth) (*model.MUser, error)
 }
 
 type JwtServiceImpl struct {
@@ -24,35 +31,146 @@ func NewJwtServiceImpl(db *gorm.DB) JwtService {
 	}
 }
 
-func (j *JwtServiceImpl) JwtAuthenticate(context context.Context, auth request.RequestAuth) ([]string, error) {
+func (j *JwtServiceImpl) JwtAuthenticate(context context.Context, auth request.RequestAuth) (*model.MUser, error) {
 	mUser := model.MUser{}
-	var roles []model.MRole
-	var authorities []string
 
 	// authenticate
-	result := j.db.Preload("MRole").First(&mUser, "email = ? and password = ?", auth.Username, auth.Password)
+	resolver, err := util.NewColumnResolver(j.db, &mUser)
+	if err != nil {
+		return nil, err
+	}
+	db := util.ApplyDeletedFilter(j.db, request.DELETED_EXCLUDE, resolver)
+	result := db.Preload("MRole").First(&mUser, "email = ?", auth.Username)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		util.VerifyDummyPassword(auth.Password)
 		return nil, errors.New("username / password invalid")
 	}
 	if result.Error != nil {
 		return nil, result.Error
 	}
 
-	// get authorities
-	levelBased := false
-	if levelBased {
-		// get level authorities
-		levelThreshold := mUser.MRole.Id
-		if err := j.db.Where("level >= ?", levelThreshold).Find(&roles).Error; err != nil {
+	ok, rehash, err := util.VerifyPassword(mUser.Password, auth.Password)
+	if err != nil {
+		util.Log("ERROR", "service", "JwtAuthenticate", "verify password error: "+err.Error())
+	}
+
+	// only the right password learns that the account is locked
+	if mUser.IsLocked {
+		lockDuration := loginLockDuration()
+		if lockDuration <= 0 || time.Since(mUser.LockedOn.Time) < lockDuration {
+			if !ok {
+				return nil, errors.New("username / password invalid")
+			}
+			return nil, constant.ErrorAccountLocked
+		}
+		util.Log("INFO", "service", "JwtAuthenticate", "lock of user "+strconv.FormatUint(uint64(mUser.Id), 10)+" expired")
+		if err := unlockMUser(j.db, mUser.Id, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if !ok {
+		if err := j.loginFailed(&mUser); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("username / password invalid")
+	}
+	if err := j.loginSucceeded(&mUser); err != nil {
+		return nil, err
+	}
+
+	policy := util.NewPasswordPolicy()
+	if policy.MaxAge > 0 {
+		changedOn, err := passwordChangedOn(j.db, &mUser)
+		if err != nil {
 			return nil, err
 		}
-		for _, v := range roles {
-			authorities = append(authorities, v.Code)
+		if policy.Expired(changedOn) {
+			return nil, constant.ErrorPasswordExpired
 		}
-	} else {
-		// get separate authorities
-		authorities = append(authorities, mUser.MRole.Code)
+	}
+	if rehash {
+		j.rehashPassword(&mUser, auth.Password)
 	}
 
-	return authorities, nil
+	return &mUser, nil
+}
+
+// userAuthorities returns the role codes and the permission codes of mUser,
+// its MRole preloaded.
+func userAuthorities(db *gorm.DB, mUser *model.MUser) ([]string, []string, error) {
+	return roleAuthorities(db, &mUser.MRole)
+}
+
+// loginFailed counts a failed login and locks the account once
+// AUTH_LOGIN_MAX_ATTEMPT is reached. Both updates are done in SQL so
+// concurrent attempts are all counted.
+func (j *JwtServiceImpl) loginFailed(mUser *model.MUser) error {
+	result := j.db.Model(&model.MUser{}).Where("id = ?", mUser.Id).
+		Update("login_attempt", gorm.Expr("login_attempt + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	maxAttempt := loginMaxAttempt()
+	if maxAttempt <= 0 {
+		return nil
+	}
+	result = j.db.Model(&model.MUser{}).
+		Where("id = ? AND login_attempt >= ? AND is_locked = ?", mUser.Id, maxAttempt, false).
+		Updates(map[string]interface{}{
+			"is_locked": true,
+			"locked_on": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		util.Log("INFO", "service", "JwtAuthenticate", "user "+strconv.FormatUint(uint64(mUser.Id), 10)+" locked after "+strconv.Itoa(maxAttempt)+" failed login(s)")
+	}
+	return nil
+}
+
+// loginSucceeded resets the failed login counter and stamps LastLogin.
+func (j *JwtServiceImpl) loginSucceeded(mUser *model.MUser) error {
+	return j.db.Model(&model.MUser{}).Where("id = ?", mUser.Id).Updates(map[string]interface{}{
+		"login_attempt": 0,
+		"last_login":    time.Now(),
+	}).Error
+}
+
+// loginMaxAttempt is AUTH_LOGIN_MAX_ATTEMPT, 5 by default. 0 disables the
+// lockout.
+func loginMaxAttempt() int {
+	maxAttempt, err := strconv.Atoi(os.Getenv("AUTH_LOGIN_MAX_ATTEMPT"))
+	if err != nil {
+		return 5
+	}
+	return maxAttempt
+}
+
+// loginLockDuration is AUTH_LOGIN_LOCK_DURATION_MS, 15 minutes by default.
+// 0 keeps the account locked until an admin unlocks it.
+func loginLockDuration() time.Duration {
+	lockDurationMs, err := strconv.ParseInt(os.Getenv("AUTH_LOGIN_LOCK_DURATION_MS"), 10, 64)
+	if err != nil {
+		return 15 * time.Minute
+	}
+	return time.Duration(lockDurationMs) * time.Millisecond
+}
+
+// rehashPassword replaces a plaintext or outdated password hash after a
+// successful login. A failure is logged only, the login still succeeds.
+func (j *JwtServiceImpl) rehashPassword(mUser *model.MUser, password string) {
+	hash, err := util.HashPassword(password)
+	if err != nil {
+		util.Log("ERROR", "service", "JwtAuthenticate", "rehash password error: "+err.Error())
+		return
+	}
+	result := j.db.Model(mUser).Update("password", hash)
+	if result.Error != nil {
+		util.Log("ERROR", "service", "JwtAuthenticate", "rehash password error: "+result.Error.Error())
+		return
+	}
+	util.Log("INFO", "service", "JwtAuthenticate", "password of user "+strconv.FormatUint(uint64(mUser.Id), 10)+" rehashed")
 }
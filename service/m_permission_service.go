@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/model/request"
+	"github.com/amsatrio/gin_notes/model/response"
+)
+
+type MPermissionService interface {
+	GetMPermission(context context.Context, id uint) (*model.MPermission, error)
+	CreateMPermission(context context.Context, mPermission *model.MPermission, mUser *model.MUser) error
+	UpdateMPermission(context context.Context, mPermission *model.MPermission, mUser *model.MUser) error
+	DeleteMPermission(context context.Context, id uint, mUser *model.MUser) error
+	SoftDeleteMPermission(context context.Context, id uint, mUser *model.MUser) error
+	RestoreMPermission(context context.Context, id uint, mUser *model.MUser) error
+	GetPageMPermission(
+		context context.Context,
+		sortRequest []request.Sort,
+		filterRequest []request.Filter,
+		searchRequest string,
+		pageInt int,
+		sizeInt int,
+		cursorRequest request.Cursor,
+		deletedRequest request.DeletedMode) (*response.Page, error)
+}
+
+type MPermissionServiceImpl struct {
+	crud CrudService[model.MPermission]
+}
+
+func NewMPermissionServiceImpl(db *gorm.DB) MPermissionService {
+	return &MPermissionServiceImpl{
+		crud: NewMPermissionCrudService(db),
+	}
+}
+
+func NewMPermissionCrudService(db *gorm.DB) CrudService[model.MPermission] {
+	return NewCrudService(db, CrudConfig[model.MPermission]{
+		UpdatableFields: []string{"Code", "Name"},
+	})
+}
+
+func (s *MPermissionServiceImpl) GetMPermission(context context.Context, id uint) (*model.MPermission, error) {
+	return s.crud.Get(context, id)
+}
+
+func (s *MPermissionServiceImpl) CreateMPermission(context context.Context, mPermission *model.MPermission, mUser *model.MUser) error {
+	return s.crud.Create(context, mPermission, mUser)
+}
+
+func (s *MPermissionServiceImpl) UpdateMPermission(context context.Context, mPermission *model.MPermission, mUser *model.MUser) error {
+	return s.crud.Update(context, mPermission, mUser)
+}
+
+func (s *MPermissionServiceImpl) DeleteMPermission(context context.Context, id uint, mUser *model.MUser) error {
+	return s.crud.Delete(context, id, mUser)
+}
+
+func (s *MPermissionServiceImpl) SoftDeleteMPermission(context context.Context, id uint, mUser *model.MUser) error {
+	return s.crud.SoftDelete(context, id, mUser)
+}
+
+func (s *MPermissionServiceImpl) RestoreMPermission(context context.Context, id uint, mUser *model.MUser) error {
+	return s.crud.Restore(context, id, mUser)
+}
+
+func (s *MPermissionServiceImpl) GetPageMPermission(
+	context context.Context,
+	sortRequest []request.Sort,
+	filterRequest []request.Filter,
+	searchRequest string,
+	pageInt int,
+	sizeInt int,
+	cursorRequest request.Cursor,
+	deletedRequest request.DeletedMode) (*response.Page, error) {
+	return s.crud.GetPage(context, sortRequest, filterRequest, searchRequest, pageInt, sizeInt, cursorRequest, deletedRequest)
+}
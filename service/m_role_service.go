@@ -2,7 +2,7 @@ package service
 
 import (
 	"context"
-	"errors"
+	"os"
 	"strconv"
 	"time"
 
@@ -20,133 +20,221 @@ type MRoleService interface {
 	UpdateMRole(context context.Context, mRole *model.MRole, mUser *model.MUser) error
 	DeleteMRole(context context.Context, id uint, mUser *model.MUser) error
 	SoftDeleteMRole(context context.Context, id uint, mUser *model.MUser) error
+	RestoreMRole(context context.Context, id uint, mUser *model.MUser) error
+	// GetMRolePermissions returns the permissions granted to role id.
+	GetMRolePermissions(context context.Context, id uint) ([]model.MPermission, error)
+	// SetMRolePermissions replaces the permissions granted to role id. Users
+	// get them on their next login or refresh.
+	SetMRolePermissions(context context.Context, id uint, permissionIds []uint, mUser *model.MUser) error
+	// GetMRoleAuthorities returns the authorities and the permissions a user
+	// of role id gets in its token.
+	GetMRoleAuthorities(context context.Context, id uint) (*response.ResponseRoleAuthorities, error)
 	GetPageMRole(
 		context context.Context,
 		sortRequest []request.Sort,
 		filterRequest []request.Filter,
 		searchRequest string,
 		pageInt int,
-		sizeInt64 int64,
-		sizeInt int) (*response.Page, error)
+		sizeInt int,
+		cursorRequest request.Cursor,
+		deletedRequest request.DeletedMode) (*response.Page, error)
 }
 
 type MRoleServiceImpl struct {
-	db *gorm.DB
+	db   *gorm.DB
+	crud CrudService[model.MRole]
 }
 
 func NewMRoleServiceImpl(db *gorm.DB) MRoleService {
 	return &MRoleServiceImpl{
-		db: db,
+		db:   db,
+		crud: NewMRoleCrudService(db),
 	}
 }
 
-func (s *MRoleServiceImpl) GetMRole(context context.Context, id uint) (*model.MRole, error) {
-	mRole := model.MRole{}
-	result := s.db.First(&mRole, id)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-
-	return &mRole, nil
+// defaultRolePermissions are granted to a role on its creation, its users
+// keep their own notes until MRoleSetPermissions grants them more.
+var defaultRolePermissions = []string{"notes:read", "notes:write"}
+
+func NewMRoleCrudService(db *gorm.DB) CrudService[model.MRole] {
+	return NewCrudService(db, CrudConfig[model.MRole]{
+		UpdatableFields: []string{"Name", "Code", "Level"},
+		Saved: func(context context.Context, tx *gorm.DB, mRole *model.MRole, old *model.MRole) error {
+			if old != nil {
+				return nil
+			}
+			return grantDefaultPermissions(tx, mRole)
+		},
+	})
 }
 
-func (s *MRoleServiceImpl) CreateMRole(context context.Context, mRole *model.MRole, mUser *model.MUser) error {
-
-	// get id creator
-
-	mRole.CreatedOn = response.JSONTime{Time: time.Now()}
-	mRole.CreatedBy = mUser.Id
-
-	util.Log("INFO", "service", "MRoleService", "CreateMRole: ")
-
-	var oldMRole model.MRole
-
-	// find data
-	result := s.db.First(&oldMRole, mRole.Id)
-	if result.Error == nil {
-		return errors.New("data exist")
+// grantDefaultPermissions grants defaultRolePermissions to mRole, soft
+// deleted ones excluded.
+func grantDefaultPermissions(tx *gorm.DB, mRole *model.MRole) error {
+	resolver, err := util.NewColumnResolver(tx, &model.MPermission{})
+	if err != nil {
+		return err
 	}
-
-	result = s.db.Create(&mRole)
+	var permissions []model.MPermission
+	result := util.ApplyDeletedFilter(tx, request.DELETED_EXCLUDE, resolver).
+		Where("code IN ?", defaultRolePermissions).Find(&permissions)
 	if result.Error != nil {
 		return result.Error
 	}
 
+	for _, mPermission := range permissions {
+		grant := model.MRolePermission{RoleId: mRole.Id, PermissionId: mPermission.Id, CreatedBy: mRole.CreatedBy, CreatedOn: mRole.CreatedOn}
+		if err := tx.Create(&grant).Error; err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (s *MRoleServiceImpl) UpdateMRole(context context.Context, mRole *model.MRole, mUser *model.MUser) error {
+func (s *MRoleServiceImpl) GetMRole(context context.Context, id uint) (*model.MRole, error) {
+	return s.crud.Get(context, id)
+}
 
-	var oldMRole *model.MRole
+func (s *MRoleServiceImpl) CreateMRole(context context.Context, mRole *model.MRole, mUser *model.MUser) error {
+	return s.crud.Create(context, mRole, mUser)
+}
 
-	// find data
-	result := s.db.First(&oldMRole, mRole.Id)
-	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-		return errors.New("data not found")
-	}
-	if result.Error != nil {
-		return result.Error
-	}
+func (s *MRoleServiceImpl) UpdateMRole(context context.Context, mRole *model.MRole, mUser *model.MUser) error {
+	return s.crud.Update(context, mRole, mUser)
+}
 
-	// update data
-	oldMRole.Name = mRole.Name
-	oldMRole.Code = mRole.Code
-	oldMRole.Level = mRole.Level
-	oldMRole.ModifiedBy = mUser.Id
-	oldMRole.ModifiedOn = response.JSONTime{Time: time.Now()}
+func (s *MRoleServiceImpl) DeleteMRole(context context.Context, id uint, mUser *model.MUser) error {
+	return s.crud.Delete(context, id, mUser)
+}
 
-	// update data for response
-	*mRole = *oldMRole
+func (s *MRoleServiceImpl) SoftDeleteMRole(context context.Context, id uint, mUser *model.MUser) error {
+	return s.crud.SoftDelete(context, id, mUser)
+}
 
-	result = s.db.Model(&oldMRole).Updates(oldMRole)
+func (s *MRoleServiceImpl) RestoreMRole(context context.Context, id uint, mUser *model.MUser) error {
+	return s.crud.Restore(context, id, mUser)
+}
 
-	if result.Error != nil {
-		return result.Error
+func (s *MRoleServiceImpl) GetMRolePermissions(context context.Context, id uint) ([]model.MPermission, error) {
+	if _, err := s.crud.Get(context, id); err != nil {
+		return nil, err
 	}
-
-	return nil
+	return rolePermissions(s.db, []uint{id})
 }
 
-func (s *MRoleServiceImpl) DeleteMRole(context context.Context, id uint, mUser *model.MUser) error {
-	var mRole model.MRole
-	result := s.db.Delete(&mRole, id)
+func (s *MRoleServiceImpl) SetMRolePermissions(context context.Context, id uint, permissionIds []uint, mUser *model.MUser) error {
+	if _, err := s.crud.Get(context, id); err != nil {
+		return err
+	}
+
+	ids := map[uint]bool{}
+	for _, permissionId := range permissionIds {
+		ids[permissionId] = true
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		resolver, err := util.NewColumnResolver(tx, &model.MPermission{})
+		if err != nil {
+			return err
+		}
+		var count int64
+		result := util.ApplyDeletedFilter(tx.Model(&model.MPermission{}), request.DELETED_EXCLUDE, resolver).
+			Where("id IN ?", permissionIds).Count(&count)
+		if result.Error != nil {
+			return result.Error
+		}
+		if count != int64(len(ids)) {
+			return &util.FieldError{Messages: map[string]string{
+				"PermissionIds": "should be the ids of existing permissions",
+			}}
+		}
+
+		if err := tx.Where("role_id = ?", id).Delete(&model.MRolePermission{}).Error; err != nil {
+			return err
+		}
+		now := response.JSONTime{Time: time.Now()}
+		for permissionId := range ids {
+			grant := model.MRolePermission{RoleId: id, PermissionId: permissionId, CreatedBy: mUser.Id, CreatedOn: now}
+			if err := tx.Create(&grant).Error; err != nil {
+				return err
+			}
+		}
+
+		util.Log("INFO", "service", "SetMRolePermissions", "role "+strconv.FormatUint(uint64(id), 10)+" has "+strconv.Itoa(len(ids))+" permission(s)")
+		return nil
+	})
+}
 
-	if result.Error != nil {
-		return result.Error
+func (s *MRoleServiceImpl) GetMRoleAuthorities(context context.Context, id uint) (*response.ResponseRoleAuthorities, error) {
+	mRole, err := s.crud.Get(context, id)
+	if err != nil {
+		return nil, err
 	}
-
-	if result.RowsAffected == 0 {
-		return errors.New("data not found")
+	authorities, permissions, err := roleAuthorities(s.db, mRole)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return &response.ResponseRoleAuthorities{Authorities: authorities, Permissions: permissions}, nil
 }
 
-func (s *MRoleServiceImpl) SoftDeleteMRole(context context.Context, id uint, mUser *model.MUser) error {
-	var oldMRole = &model.MRole{}
-
-	// find data
-	result := s.db.First(&oldMRole, id)
-	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-		return errors.New("data not found")
-	}
-	if result.Error != nil {
-		return result.Error
-	}
+// roleAuthorities returns the role codes and the permission codes granted by
+// mRole. When AUTH_ROLE_HIERARCHY is true a role also gets the codes of every
+// role with a greater Level, a lower Level being more privileged.
+func roleAuthorities(db *gorm.DB, mRole *model.MRole) ([]string, []string, error) {
+	authorities := []string{mRole.Code}
+	roleIds := []uint{mRole.Id}
+
+	// a user without a role inherits nothing
+	if roleHierarchyEnabled() && mRole.Id != 0 {
+		resolver, err := util.NewColumnResolver(db, &model.MRole{})
+		if err != nil {
+			return nil, nil, err
+		}
+		var roles []model.MRole
+		result := util.ApplyDeletedFilter(db, request.DELETED_EXCLUDE, resolver).
+			Where("level > ?", mRole.Level).Order("level").Order("code").Find(&roles)
+		if result.Error != nil {
+			return nil, nil, result.Error
+		}
+		for _, v := range roles {
+			authorities = append(authorities, v.Code)
+			roleIds = append(roleIds, v.Id)
+		}
+	}
+
+	permissions := []string{}
+	rows, err := rolePermissions(db, roleIds)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, v := range rows {
+		permissions = append(permissions, v.Code)
+	}
+
+	return authorities, permissions, nil
+}
 
-	// update data
-	mRole := oldMRole
-	mRole.DeletedOn = response.JSONTime{Time: time.Now()}
-	mRole.DeletedBy = mUser.Id
-	bool_true := true
-	mRole.IsDelete = &bool_true
+// roleHierarchyEnabled is AUTH_ROLE_HIERARCHY, false by default.
+func roleHierarchyEnabled() bool {
+	return os.Getenv("AUTH_ROLE_HIERARCHY") == "true"
+}
 
-	result = s.db.Model(&oldMRole).Updates(mRole)
+// rolePermissions returns the permissions granted to any of roleIds, soft
+// deleted ones excluded.
+func rolePermissions(db *gorm.DB, roleIds []uint) ([]model.MPermission, error) {
+	resolver, err := util.NewColumnResolver(db, &model.MPermission{})
+	if err != nil {
+		return nil, err
+	}
 
+	var permissions []model.MPermission
+	result := util.ApplyDeletedFilter(db, request.DELETED_EXCLUDE, resolver).
+		Where("id IN (?)", db.Model(&model.MRolePermission{}).Select("permission_id").Where("role_id IN ?", roleIds)).
+		Order("code").Find(&permissions)
 	if result.Error != nil {
-		return result.Error
+		return nil, result.Error
 	}
-
-	return nil
+	return permissions, nil
 }
 
 func (s *MRoleServiceImpl) GetPageMRole(
@@ -155,77 +243,8 @@ func (s *MRoleServiceImpl) GetPageMRole(
 	filterRequest []request.Filter,
 	searchRequest string,
 	pageInt int,
-	sizeInt64 int64,
-	sizeInt int) (*response.Page, error) {
-
-	util.Log("INFO", "service", "GetPageMRole", "")
-
-	var mRoles []model.MRole
-	var mRole model.MRole
-	mRoleMap := util.GetJSONFieldTypes(mRole)
-
-	// Create a DB instance and build the base query
-	db := s.db
-
-	// apply sorting
-	db = util.ApplySorting(db, sortRequest)
-
-	// apply filtering
-	db = util.ApplyFiltering(db, filterRequest)
-
-	// apply global search
-	db = util.ApplyGlobalSearch(db, searchRequest, mRoleMap)
-
-	// Calculate the total data size without considering _size
-	totalElements := db.Find(&mRoles).RowsAffected
-
-	// Calculate the total number of pages
-	totalPages := totalElements / sizeInt64
-	if totalElements%sizeInt64 != 0 {
-		totalPages++
-	}
-
-	// paginate
-	result := db.Scopes(util.ApplyPaginate(pageInt, sizeInt)).Find(&mRoles)
-
-	if result.Error != nil {
-		return nil, result.Error
-	}
-
-	lastPage := int64(pageInt) == totalPages-1
-	firstPage := pageInt == 0
-
-	// prepare page
-	sort := response.Sort{
-		Empty:    totalElements <= 0,
-		Sorted:   true,
-		Unsorted: false,
-	}
-
-	pageable := response.Pageable{
-		Offset:     pageInt * sizeInt,
-		PageNumber: pageInt,
-		PageSize:   sizeInt,
-		Paged:      true,
-		UnPaged:    false,
-		Sort:       sort,
-	}
-
-	page := response.Page{
-		Content:          mRoles,
-		Pageable:         pageable,
-		Sort:             sort,
-		TotalPages:       totalPages,
-		TotalElements:    totalElements,
-		Size:             sizeInt,
-		Number:           pageInt,
-		NumberOfElements: sizeInt,
-		Last:             lastPage,
-		First:            firstPage,
-		Empty:            sort.Empty,
-	}
-
-	util.Log("INFO", "service", "GetPageMRole", "sort is empty: "+strconv.FormatBool(sort.Empty))
-
-	return &page, nil
+	sizeInt int,
+	cursorRequest request.Cursor,
+	deletedRequest request.DeletedMode) (*response.Page, error) {
+	return s.crud.GetPage(context, sortRequest, filterRequest, searchRequest, pageInt, sizeInt, cursorRequest, deletedRequest)
 }
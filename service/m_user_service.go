@@ -3,7 +3,6 @@ package service
 import (
 	"context"
 	"errors"
-	"strconv"
 	"time"
 
 	"gorm.io/gorm"
@@ -21,39 +20,105 @@ type MUserService interface {
 	UpdateMUser(context context.Context, mUser *model.MUser, mUserAccess *model.MUser) error
 	DeleteMUser(context context.Context, id uint, mUserAccess *model.MUser) error
 	SoftDeleteMUser(context context.Context, id uint, mUserAccess *model.MUser) error
+	RestoreMUser(context context.Context, id uint, mUserAccess *model.MUser) error
+	UnlockMUser(context context.Context, id uint, mUserAccess *model.MUser) error
 	GetPageMUser(
 		context context.Context,
 		sortRequest []request.Sort,
 		filterRequest []request.Filter,
 		searchRequest string,
 		pageInt int,
-		sizeInt64 int64,
-		sizeInt int) (*response.Page, error)
+		sizeInt int,
+		cursorRequest request.Cursor,
+		deletedRequest request.DeletedMode) (*response.Page, error)
 }
 
 type MUserServiceImpl struct {
-	db *gorm.DB
+	db   *gorm.DB
+	crud CrudService[model.MUser]
 }
 
 func NewMUserServiceImpl(db *gorm.DB) MUserService {
 	return &MUserServiceImpl{
-		db: db,
+		db:   db,
+		crud: NewMUserCrudService(db),
 	}
 }
 
-func (s *MUserServiceImpl) GetMUser(context context.Context, id uint) (*model.MUser, error) {
-	mUser := model.MUser{}
-	result := s.db.First(&mUser, id)
-	if result.Error != nil {
-		return nil, result.Error
-	}
+func NewMUserCrudService(db *gorm.DB) CrudService[model.MUser] {
+	return NewCrudService(db, CrudConfig[model.MUser]{
+		UpdatableFields: []string{"BiodataId", "RoleId", "Email", "Password"},
+		Validate: func(context context.Context, mUser *model.MUser, mUserAccess *model.MUser) error {
+			if mUser.Email != "" && !util.ValidateEmail(mUser.Email) {
+				return errors.New("email is invalid")
+			}
+			return nil
+		},
+		Prepare: func(context context.Context, mUser *model.MUser, old *model.MUser) error {
+			// the password is never sent back, an empty one keeps the old hash
+			if mUser.Password == "" {
+				if old != nil {
+					mUser.Password = old.Password
+				}
+				return nil
+			}
+
+			userId, current := mUser.Id, ""
+			if old != nil {
+				userId, current = old.Id, old.Password
+			}
+			if err := checkPassword(db, util.NewPasswordPolicy(), userId, current, mUser.Password); err != nil {
+				return err
+			}
+
+			hash, err := util.HashPassword(mUser.Password)
+			if err != nil {
+				return err
+			}
+			mUser.Password = hash
+			return nil
+		},
+		Saved: func(context context.Context, tx *gorm.DB, mUser *model.MUser, old *model.MUser) error {
+			resetFor, changedBy := RESET_FOR_CREATE, mUser.CreatedBy
+			if old != nil {
+				if mUser.Password == old.Password {
+					return nil
+				}
+				resetFor, changedBy = RESET_FOR_UPDATE, mUser.ModifiedBy
+
+				// the sessions opened with the old password end
+				if _, err := NewRefreshTokenServiceImpl(tx).RevokeUser(context, mUser.Id); err != nil {
+					return err
+				}
+			}
+			if mUser.Password == "" {
+				return nil
+			}
+			return recordPassword(tx, util.NewPasswordPolicy(), mUser.Id, mUser.Password, resetFor, changedBy)
+		},
+		Committed: func(context context.Context, mUser *model.MUser, old *model.MUser) error {
+			if old == nil || mUser.Password == old.Password {
+				return nil
+			}
+			// the access tokens got with the old password end
+			return denyUserTokens(context, old.Email)
+		},
+	})
+}
 
-	return &mUser, nil
+func (s *MUserServiceImpl) GetMUser(context context.Context, id uint) (*model.MUser, error) {
+	return s.crud.Get(context, id)
 }
 
 func (s *MUserServiceImpl) GetMUserByEmail(context context.Context, email string) (*model.MUser, error) {
+	resolver, err := util.NewColumnResolver(s.db, &model.MUser{})
+	if err != nil {
+		return nil, err
+	}
+
 	mUser := model.MUser{}
-	result := s.db.Where("email = ?", email).First(&mUser)
+	db := util.ApplyDeletedFilter(s.db, request.DELETED_EXCLUDE, resolver)
+	result := db.Where("email = ?", email).First(&mUser)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -62,106 +127,46 @@ func (s *MUserServiceImpl) GetMUserByEmail(context context.Context, email string
 }
 
 func (s *MUserServiceImpl) CreateMUser(context context.Context, mUser *model.MUser, mUserAccess *model.MUser) error {
-
-	// get id creator
-
-	mUser.CreatedOn = response.JSONTime{Time: time.Now()}
-	mUser.CreatedBy = mUserAccess.Id
-
-	util.Log("INFO", "service", "MUserService", "CreateMUser: ")
-
-	var oldMUser model.MUser
-
-	// find data
-	result := s.db.First(&oldMUser, mUser.Id)
-	if result.Error == nil {
-		return errors.New("data exist")
-	}
-
-	result = s.db.Create(&mUser)
-	if result.Error != nil {
-		return result.Error
-	}
-
-	return nil
+	return s.crud.Create(context, mUser, mUserAccess)
 }
 
 func (s *MUserServiceImpl) UpdateMUser(context context.Context, mUser *model.MUser, mUserAccess *model.MUser) error {
-
-	var oldMUser *model.MUser
-
-	// find data
-	result := s.db.First(&oldMUser, mUser.Id)
-	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-		return errors.New("data not found")
-	}
-	if result.Error != nil {
-		return result.Error
-	}
-
-	// update data
-	oldMUser.BiodataId = mUser.BiodataId
-	oldMUser.RoleId = mUser.RoleId
-	oldMUser.Email = mUser.Email
-	oldMUser.LoginAttempt = mUser.LoginAttempt
-	oldMUser.Password = mUser.Password
-	oldMUser.IsLocked = mUser.IsLocked
-	oldMUser.LastLogin = mUser.LastLogin
-	oldMUser.ModifiedBy = mUserAccess.Id
-	oldMUser.ModifiedOn = response.JSONTime{Time: time.Now()}
-
-	// update data for response
-	*mUser = *oldMUser
-
-	result = s.db.Model(&oldMUser).Updates(oldMUser)
-
-	if result.Error != nil {
-		return result.Error
-	}
-
-	return nil
+	return s.crud.Update(context, mUser, mUserAccess)
 }
 
 func (s *MUserServiceImpl) DeleteMUser(context context.Context, id uint, mUserAccess *model.MUser) error {
-	var mUser model.MUser
-	result := s.db.Delete(&mUser, id)
-
-	if result.Error != nil {
-		return result.Error
-	}
-
-	if result.RowsAffected == 0 {
-		return errors.New("data not found")
-	}
-	return nil
+	return s.crud.Delete(context, id, mUserAccess)
 }
 
 func (s *MUserServiceImpl) SoftDeleteMUser(context context.Context, id uint, mUserAccess *model.MUser) error {
-	var oldMUser = &model.MUser{}
-
-	// find data
-	result := s.db.First(&oldMUser, id)
-	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-		return errors.New("data not found")
-	}
-	if result.Error != nil {
-		return result.Error
-	}
-
-	// update data
-	mUser := oldMUser
-	mUser.DeletedOn = response.JSONTime{Time: time.Now()}
-	mUser.DeletedBy = mUser.Id
-	bool_true := true
-	mUser.IsDelete = &bool_true
+	return s.crud.SoftDelete(context, id, mUserAccess)
+}
 
-	result = s.db.Model(&oldMUser).Updates(mUser)
+func (s *MUserServiceImpl) RestoreMUser(context context.Context, id uint, mUserAccess *model.MUser) error {
+	return s.crud.Restore(context, id, mUserAccess)
+}
 
-	if result.Error != nil {
-		return result.Error
+// UnlockMUser clears the lock and the failed login counter of a user.
+func (s *MUserServiceImpl) UnlockMUser(context context.Context, id uint, mUserAccess *model.MUser) error {
+	if _, err := s.crud.Get(context, id); err != nil {
+		return err
 	}
+	return unlockMUser(s.db, id, mUserAccess)
+}
 
-	return nil
+// unlockMUser resets the lockout columns, mUserAccess is nil when the lock
+// expired.
+func unlockMUser(db *gorm.DB, id uint, mUserAccess *model.MUser) error {
+	values := map[string]interface{}{
+		"login_attempt": 0,
+		"is_locked":     false,
+		"locked_on":     nil,
+	}
+	if mUserAccess != nil {
+		values["modified_by"] = mUserAccess.Id
+		values["modified_on"] = time.Now()
+	}
+	return db.Model(&model.MUser{}).Where("id = ?", id).Updates(values).Error
 }
 
 func (s *MUserServiceImpl) GetPageMUser(
@@ -170,77 +175,8 @@ func (s *MUserServiceImpl) GetPageMUser(
 	filterRequest []request.Filter,
 	searchRequest string,
 	pageInt int,
-	sizeInt64 int64,
-	sizeInt int) (*response.Page, error) {
-
-	util.Log("INFO", "service", "GetPageMUser", "")
-
-	var mUsers []model.MUser
-	var mUser model.MUser
-	mUserMap := util.GetJSONFieldTypes(mUser)
-
-	// Create a DB instance and build the base query
-	db := s.db
-
-	// apply sorting
-	db = util.ApplySorting(db, sortRequest)
-
-	// apply filtering
-	db = util.ApplyFiltering(db, filterRequest)
-
-	// apply global search
-	db = util.ApplyGlobalSearch(db, searchRequest, mUserMap)
-
-	// Calculate the total data size without considering _size
-	totalElements := db.Find(&mUsers).RowsAffected
-
-	// Calculate the total number of pages
-	totalPages := totalElements / sizeInt64
-	if totalElements%sizeInt64 != 0 {
-		totalPages++
-	}
-
-	// paginate
-	result := db.Scopes(util.ApplyPaginate(pageInt, sizeInt)).Find(&mUsers)
-
-	if result.Error != nil {
-		return nil, result.Error
-	}
-
-	lastPage := int64(pageInt) == totalPages-1
-	firstPage := pageInt == 0
-
-	// prepare page
-	sort := response.Sort{
-		Empty:    totalElements <= 0,
-		Sorted:   true,
-		Unsorted: false,
-	}
-
-	pageable := response.Pageable{
-		Offset:     pageInt * sizeInt,
-		PageNumber: pageInt,
-		PageSize:   sizeInt,
-		Paged:      true,
-		UnPaged:    false,
-		Sort:       sort,
-	}
-
-	page := response.Page{
-		Content:          mUsers,
-		Pageable:         pageable,
-		Sort:             sort,
-		TotalPages:       totalPages,
-		TotalElements:    totalElements,
-		Size:             sizeInt,
-		Number:           pageInt,
-		NumberOfElements: sizeInt,
-		Last:             lastPage,
-		First:            firstPage,
-		Empty:            sort.Empty,
-	}
-
-	util.Log("INFO", "service", "GetPageMUser", "sort is empty: "+strconv.FormatBool(sort.Empty))
-
-	return &page, nil
+	sizeInt int,
+	cursorRequest request.Cursor,
+	deletedRequest request.DeletedMode) (*response.Page, error) {
+	return s.crud.GetPage(context, sortRequest, filterRequest, searchRequest, pageInt, sizeInt, cursorRequest, deletedRequest)
 }
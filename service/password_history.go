@@ -0,0 +1,101 @@
+package service
+
+import (
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/model/response"
+	"github.com/amsatrio/gin_notes/util"
+)
+
+const (
+	RESET_FOR_CREATE = "CREATE"
+	RESET_FOR_UPDATE = "UPDATE"
+)
+
+// checkPassword applies policy to a new password of user userId. current is
+// the stored password of the user, empty on create; it is checked against the
+// reuse ban too since users created before the history have no rows.
+func checkPassword(db *gorm.DB, policy *util.PasswordPolicy, userId uint, current string, password string) error {
+	if err := policy.Check("Password", password); err != nil {
+		return err
+	}
+	if policy.HistorySize == 0 {
+		return nil
+	}
+
+	hashes := []string{}
+	if current != "" {
+		hashes = append(hashes, current)
+	}
+	if userId != 0 {
+		var history []model.TResetPassword
+		result := db.Where("user_id = ? AND password_hash <> ?", userId, "").
+			Order("id DESC").Limit(policy.HistorySize).Find(&history)
+		if result.Error != nil {
+			return result.Error
+		}
+		for _, row := range history {
+			hashes = append(hashes, row.PasswordHash)
+		}
+	}
+
+	for _, hash := range hashes {
+		ok, _, err := util.VerifyPassword(hash, password)
+		if err != nil {
+			util.Log("ERROR", "service", "checkPassword", "verify password error: "+err.Error())
+			continue
+		}
+		if ok {
+			return &util.FieldError{Messages: map[string]string{
+				"Password": "should not be one of the last " + strconv.Itoa(policy.HistorySize) + " password(s)",
+			}}
+		}
+	}
+	return nil
+}
+
+// recordPassword adds hash to the password history of user userId and drops
+// the rows the policy no longer needs.
+func recordPassword(db *gorm.DB, policy *util.PasswordPolicy, userId uint, hash string, resetFor string, createdBy uint) error {
+	row := model.TResetPassword{
+		UserId:       userId,
+		PasswordHash: hash,
+		ResetFor:     resetFor,
+		CreatedBy:    createdBy,
+		CreatedOn:    response.JSONTime{Time: time.Now()},
+	}
+	if err := db.Create(&row).Error; err != nil {
+		return err
+	}
+
+	// the latest row is kept for the password age
+	keep := policy.HistorySize
+	if keep < 1 {
+		keep = 1
+	}
+	var keepIds []uint
+	result := db.Model(&model.TResetPassword{}).Where("user_id = ?", userId).
+		Order("id DESC").Limit(keep).Pluck("id", &keepIds)
+	if result.Error != nil {
+		return result.Error
+	}
+	return db.Where("user_id = ? AND id NOT IN ?", userId, keepIds).Delete(&model.TResetPassword{}).Error
+}
+
+// passwordChangedOn returns when the password of mUser was set, its creation
+// when there is no history.
+func passwordChangedOn(db *gorm.DB, mUser *model.MUser) (time.Time, error) {
+	var row model.TResetPassword
+	result := db.Where("user_id = ?", mUser.Id).Order("id DESC").Limit(1).Find(&row)
+	if result.Error != nil {
+		return time.Time{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return mUser.CreatedOn.Time, nil
+	}
+	return row.CreatedOn.Time, nil
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/amsatrio/gin_notes/constant"
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/model/request"
+	"github.com/amsatrio/gin_notes/model/response"
+	"github.com/amsatrio/gin_notes/util"
+)
+
+const (
+	TOKEN_USED_FOR_RESET_PASSWORD = "RESET_PASSWORD"
+	RESET_FOR_FORGOT_PASSWORD     = "FORGOT_PASSWORD"
+)
+
+type PasswordResetService interface {
+	// ForgotPassword mails a reset token to email. An unknown email is not
+	// an error. The caller does not wait for it, so its reply does not tell
+	// which emails are registered.
+	ForgotPassword(context context.Context, email string) error
+	// ResetPassword sets the password of the user of a reset token and uses
+	// the token up.
+	ResetPassword(context context.Context, token string, password string) error
+}
+
+type PasswordResetServiceImpl struct {
+	db     *gorm.DB
+	mailer util.Mailer
+}
+
+func NewPasswordResetServiceImpl(db *gorm.DB, mailer util.Mailer) PasswordResetService {
+	return &PasswordResetServiceImpl{
+		db:     db,
+		mailer: mailer,
+	}
+}
+
+func (s *PasswordResetServiceImpl) ForgotPassword(context context.Context, email string) error {
+	mUser, err := NewMUserServiceImpl(s.db).GetMUserByEmail(context, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		util.Log("INFO", "service", "ForgotPassword", "unknown email")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := util.GenerateToken()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	expiredOn := now.Add(resetTokenExpired())
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// only the last token of a user can be used
+		result := tx.Model(&model.TToken{}).
+			Where("user_id = ? AND used_for = ? AND is_expired = ?", mUser.Id, TOKEN_USED_FOR_RESET_PASSWORD, false).
+			Update("is_expired", true)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Create(&model.TToken{
+			Email:     mUser.Email,
+			UserId:    mUser.Id,
+			Token:     util.HashToken(token),
+			ExpiredOn: response.JSONTime{Time: expiredOn},
+			UsedFor:   TOKEN_USED_FOR_RESET_PASSWORD,
+			CreatedBy: mUser.Id,
+			CreatedOn: response.JSONTime{Time: now},
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	body := "Use this token to reset your password: " + token + "\n"
+	if url := os.Getenv("AUTH_RESET_PASSWORD_URL"); url != "" {
+		body = "Open this link to reset your password: " + url + token + "\n"
+	}
+	body += "It expires on " + expiredOn.Format("2006-01-02 15:04:05") + ". Ignore this mail if you did not ask for it.\n"
+
+	return s.mailer.Send(mUser.Email, "Reset your password", body)
+}
+
+func (s *PasswordResetServiceImpl) ResetPassword(context context.Context, token string, password string) error {
+	policy := util.NewPasswordPolicy()
+
+	var email string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		resolver, err := util.NewColumnResolver(tx, &model.TToken{})
+		if err != nil {
+			return err
+		}
+
+		tToken := model.TToken{}
+		result := util.ApplyDeletedFilter(tx, request.DELETED_EXCLUDE, resolver).
+			Where("token = ? AND used_for = ? AND is_expired = ?", util.HashToken(token), TOKEN_USED_FOR_RESET_PASSWORD, false).
+			First(&tToken)
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return constant.ErrorTokenInvalid
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		if time.Now().After(tToken.ExpiredOn.Time) {
+			return constant.ErrorTokenInvalid
+		}
+
+		// use the token up, only one of two concurrent requests gets a row
+		now := time.Now()
+		result = tx.Model(&model.TToken{}).
+			Where("id = ? AND is_expired = ?", tToken.Id, false).
+			Updates(map[string]interface{}{
+				"is_expired":  true,
+				"modified_by": tToken.UserId,
+				"modified_on": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return constant.ErrorTokenInvalid
+		}
+
+		mUser, err := NewMUserServiceImpl(tx).GetMUser(context, tToken.UserId)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return constant.ErrorTokenInvalid
+		}
+		if err != nil {
+			return err
+		}
+
+		// a password the policy refuses rolls the token back, so it can be
+		// used again with another password
+		if err := checkPassword(tx, policy, mUser.Id, mUser.Password, password); err != nil {
+			return err
+		}
+		hash, err := util.HashPassword(password)
+		if err != nil {
+			return err
+		}
+
+		// a reset also lifts a lockout
+		result = tx.Model(&model.MUser{}).Where("id = ?", mUser.Id).Updates(map[string]interface{}{
+			"password":      hash,
+			"login_attempt": 0,
+			"is_locked":     false,
+			"locked_on":     nil,
+			"modified_by":   mUser.Id,
+			"modified_on":   now,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// the sessions opened with the old password end
+		if _, err := NewRefreshTokenServiceImpl(tx).RevokeUser(context, mUser.Id); err != nil {
+			return err
+		}
+		email = mUser.Email
+
+		util.Log("INFO", "service", "ResetPassword", "password of user "+strconv.FormatUint(uint64(mUser.Id), 10)+" reset")
+		return recordPassword(tx, policy, mUser.Id, hash, RESET_FOR_FORGOT_PASSWORD, mUser.Id)
+	})
+	if err != nil {
+		return err
+	}
+
+	// the access tokens got with the old password end once the reset is
+	// committed
+	return denyUserTokens(context, email)
+}
+
+// resetTokenExpired is AUTH_RESET_TOKEN_EXPIRED_MS, 30 minutes by default.
+func resetTokenExpired() time.Duration {
+	expiredMs, err := strconv.ParseInt(os.Getenv("AUTH_RESET_TOKEN_EXPIRED_MS"), 10, 64)
+	if err != nil || expiredMs <= 0 {
+		return 30 * time.Minute
+	}
+	return time.Duration(expiredMs) * time.Millisecond
+}
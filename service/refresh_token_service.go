@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/amsatrio/gin_notes/constant"
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/model/request"
+	"github.com/amsatrio/gin_notes/model/response"
+	"github.com/amsatrio/gin_notes/util"
+)
+
+// RefreshTokenService keeps the refresh tokens in t_refresh_token. A login
+// starts a token family (the session), every refresh uses its token up and
+// issues the next one of the family. A used token presented again means it
+// was stolen, so the whole family is revoked.
+type RefreshTokenService interface {
+	// Issue starts a session for mUser, its MRole preloaded, and returns its
+	// first tokens.
+	Issue(context context.Context, mUser *model.MUser) (*response.ResponseAuth, error)
+	// Rotate exchanges refreshToken for the next tokens of its session. The
+	// authorities are read again so role changes apply on refresh.
+	Rotate(context context.Context, refreshToken string) (*response.ResponseAuth, error)
+	RevokeSession(context context.Context, sessionId string) error
+	// RevokeUser revokes every session of a user and returns their count.
+	RevokeUser(context context.Context, userId uint) (int64, error)
+	// IsSessionActive reports whether sessionId has a token that is not
+	// revoked, an unknown session is not active.
+	IsSessionActive(context context.Context, sessionId string) (bool, error)
+}
+
+type RefreshTokenServiceImpl struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenServiceImpl(db *gorm.DB) RefreshTokenService {
+	return &RefreshTokenServiceImpl{
+		db: db,
+	}
+}
+
+func (s *RefreshTokenServiceImpl) Issue(context context.Context, mUser *model.MUser) (*response.ResponseAuth, error) {
+	sessionId, err := util.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(mUser, sessionId)
+}
+
+func (s *RefreshTokenServiceImpl) Rotate(context context.Context, refreshToken string) (*response.ResponseAuth, error) {
+	if _, err := util.JwtExtractAllClaims(refreshToken, "refresh_token"); err != nil {
+		return nil, constant.ErrorTokenInvalid
+	}
+
+	tRefreshToken := model.TRefreshToken{}
+	result := s.db.Where("token_hash = ?", util.HashToken(refreshToken)).First(&tRefreshToken)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, constant.ErrorTokenInvalid
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if !tRefreshToken.RevokedOn.IsZero() || time.Now().After(tRefreshToken.ExpiredOn.Time) {
+		return nil, constant.ErrorTokenInvalid
+	}
+
+	// use the token up, only one of two concurrent requests gets the row
+	result = s.db.Model(&model.TRefreshToken{}).
+		Where("id = ? AND used_on IS NULL AND revoked_on IS NULL", tRefreshToken.Id).
+		Update("used_on", time.Now())
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		util.Log("INFO", "service", "RefreshTokenService", "refresh token of user "+strconv.FormatUint(uint64(tRefreshToken.UserId), 10)+" reused, session revoked")
+		if err := s.RevokeSession(context, tRefreshToken.FamilyId); err != nil {
+			return nil, err
+		}
+		return nil, constant.ErrorTokenReused
+	}
+
+	// a deleted user gets no new token
+	resolver, err := util.NewColumnResolver(s.db, &model.MUser{})
+	if err != nil {
+		return nil, err
+	}
+	mUser := model.MUser{}
+	result = util.ApplyDeletedFilter(s.db, request.DELETED_EXCLUDE, resolver).
+		Preload("MRole").First(&mUser, tRefreshToken.UserId)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, constant.ErrorTokenInvalid
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return s.issue(&mUser, tRefreshToken.FamilyId)
+}
+
+func (s *RefreshTokenServiceImpl) RevokeSession(context context.Context, sessionId string) error {
+	return s.db.Model(&model.TRefreshToken{}).
+		Where("family_id = ? AND revoked_on IS NULL", sessionId).
+		Update("revoked_on", time.Now()).Error
+}
+
+func (s *RefreshTokenServiceImpl) RevokeUser(context context.Context, userId uint) (int64, error) {
+	var sessionIds []string
+	result := s.db.Model(&model.TRefreshToken{}).
+		Where("user_id = ? AND revoked_on IS NULL", userId).
+		Distinct().Pluck("family_id", &sessionIds)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	result = s.db.Model(&model.TRefreshToken{}).
+		Where("user_id = ? AND revoked_on IS NULL", userId).
+		Update("revoked_on", time.Now())
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	util.Log("INFO", "service", "RefreshTokenService", "sessions of user "+strconv.FormatUint(uint64(userId), 10)+" revoked")
+	return int64(len(sessionIds)), nil
+}
+
+func (s *RefreshTokenServiceImpl) IsSessionActive(context context.Context, sessionId string) (bool, error) {
+	var count int64
+	result := s.db.Model(&model.TRefreshToken{}).
+		Where("family_id = ? AND revoked_on IS NULL", sessionId).
+		Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
+
+// issue signs the tokens of session sessionId and stores the refresh token.
+func (s *RefreshTokenServiceImpl) issue(mUser *model.MUser, sessionId string) (*response.ResponseAuth, error) {
+	authorities, permissions, err := userAuthorities(s.db, mUser)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := util.JwtGenerateMainToken(mUser.Email, authorities, permissions, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := util.JwtGenerateRefreshToken(mUser.Email, authorities, permissions, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	refreshClaims, err := util.JwtExtractAllClaims(refreshToken, "refresh_token")
+	if err != nil {
+		return nil, err
+	}
+
+	result := s.db.Create(&model.TRefreshToken{
+		UserId:    mUser.Id,
+		Email:     mUser.Email,
+		FamilyId:  sessionId,
+		TokenHash: util.HashToken(refreshToken),
+		ExpiredOn: response.JSONTime{Time: util.JwtGetExpiration(refreshClaims)},
+		CreatedOn: response.JSONTime{Time: time.Now()},
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &response.ResponseAuth{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiredIn:    os.Getenv("AUTH_JWT_TOKEN_EXPIRED_MS"),
+	}, nil
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/model/response"
+	"github.com/amsatrio/gin_notes/util"
+)
+
+// restoredRevisionKey is the context key of the revision a note update
+// restores, see recordNoteRevision.
+type restoredRevisionKey struct{}
+
+// TNoteRevisionService reads the revisions of the notes in t_note_revision,
+// which are written by the updates of the notes. The caller checks the user
+// may read the note, or update it for a restore.
+type TNoteRevisionService interface {
+	// GetTNoteRevisions returns the revisions of a note, the latest first.
+	GetTNoteRevisions(context context.Context, noteId uint) ([]model.TNoteRevision, error)
+	GetTNoteRevision(context context.Context, noteId uint, revision uint) (*model.TNoteRevision, error)
+	// DiffTNoteRevisions returns the line diff between two revisions of a
+	// note, to being the latest revision when 0.
+	DiffTNoteRevisions(context context.Context, noteId uint, from uint, to uint) (*response.ResponseNoteRevisionDiff, error)
+	// RestoreTNoteRevision updates the note with crud to the text of one of
+	// its revisions, which saves it as a new revision.
+	RestoreTNoteRevision(context context.Context, crud CrudService[model.MNotes], noteId uint, revision uint, mUser *model.MUser) (*model.MNotes, error)
+}
+
+type TNoteRevisionServiceImpl struct {
+	db *gorm.DB
+}
+
+func NewTNoteRevisionServiceImpl(db *gorm.DB) TNoteRevisionService {
+	return &TNoteRevisionServiceImpl{
+		db: db,
+	}
+}
+
+func (s *TNoteRevisionServiceImpl) GetTNoteRevisions(context context.Context, noteId uint) ([]model.TNoteRevision, error) {
+	revisions := []model.TNoteRevision{}
+	result := s.db.Where("note_id = ?", noteId).Order("revision DESC").Find(&revisions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return revisions, nil
+}
+
+func (s *TNoteRevisionServiceImpl) GetTNoteRevision(context context.Context, noteId uint, revision uint) (*model.TNoteRevision, error) {
+	tNoteRevision := model.TNoteRevision{}
+	result := s.db.Where("note_id = ? AND revision = ?", noteId, revision).First(&tNoteRevision)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &tNoteRevision, nil
+}
+
+func (s *TNoteRevisionServiceImpl) DiffTNoteRevisions(context context.Context, noteId uint, from uint, to uint) (*response.ResponseNoteRevisionDiff, error) {
+	fromRevision, err := s.GetTNoteRevision(context, noteId, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toRevision := &model.TNoteRevision{}
+	if to == 0 {
+		result := s.db.Where("note_id = ?", noteId).Order("revision DESC").First(toRevision)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+	} else {
+		toRevision, err = s.GetTNoteRevision(context, noteId, to)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &response.ResponseNoteRevisionDiff{
+		NoteId:  noteId,
+		From:    fromRevision.Revision,
+		To:      toRevision.Revision,
+		Title:   util.DiffLines(fromRevision.Title, toRevision.Title),
+		Content: util.DiffLines(fromRevision.Content, toRevision.Content),
+	}, nil
+}
+
+func (s *TNoteRevisionServiceImpl) RestoreTNoteRevision(context context.Context, crud CrudService[model.MNotes], noteId uint, revision uint, mUser *model.MUser) (*model.MNotes, error) {
+	tNoteRevision, err := s.GetTNoteRevision(context, noteId, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	mNotes := model.MNotes{
+		Id:      noteId,
+		Title:   tNoteRevision.Title,
+		Content: tNoteRevision.Content,
+	}
+	restoreContext := contextWithRestoredRevision(context, revision)
+	if err := crud.Update(restoreContext, &mNotes, mUser); err != nil {
+		return nil, err
+	}
+
+	util.Log("INFO", "service", "RestoreTNoteRevision", "note "+strconv.FormatUint(uint64(noteId), 10)+" restored to revision "+strconv.FormatUint(uint64(revision), 10))
+	return &mNotes, nil
+}
+
+func contextWithRestoredRevision(parent context.Context, revision uint) context.Context {
+	return context.WithValue(parent, restoredRevisionKey{}, revision)
+}
+
+// recordNoteRevision is the CrudConfig.Saved of the notes, it adds the saved
+// note as its next revision then drops the revisions past
+// noteRevisionRetention.
+func recordNoteRevision(context context.Context, tx *gorm.DB, mNotes *model.MNotes, old *model.MNotes) error {
+	createdBy, createdOn := mNotes.CreatedBy, mNotes.CreatedOn
+	if old != nil {
+		createdBy, createdOn = mNotes.ModifiedBy, mNotes.ModifiedOn
+	}
+	restoredFrom, _ := context.Value(restoredRevisionKey{}).(uint)
+
+	revision, err := nextRevision(tx, mNotes.Id)
+	if err != nil {
+		return err
+	}
+
+	row := model.TNoteRevision{
+		NoteId:       mNotes.Id,
+		Revision:     revision,
+		Title:        mNotes.Title,
+		Content:      mNotes.Content,
+		RestoredFrom: restoredFrom,
+		CreatedBy:    createdBy,
+		CreatedOn:    createdOn,
+	}
+	if err := tx.Create(&row).Error; err != nil {
+		return err
+	}
+
+	keep := noteRevisionRetention()
+	if keep == 0 {
+		return nil
+	}
+	var keepIds []uint
+	result := tx.Model(&model.TNoteRevision{}).Where("note_id = ?", mNotes.Id).
+		Order("revision DESC").Limit(keep).Pluck("id", &keepIds)
+	if result.Error != nil {
+		return result.Error
+	}
+	return tx.Where("note_id = ? AND id NOT IN ?", mNotes.Id, keepIds).Delete(&model.TNoteRevision{}).Error
+}
+
+// nextRevision returns the number of the next revision of the note noteId.
+// The note row stays locked until tx ends, so concurrent saves of the note
+// take their numbers one after the other instead of the same one.
+func nextRevision(tx *gorm.DB, noteId uint) (uint, error) {
+	var locked []uint
+	result := tx.Model(&model.MNotes{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", noteId).Pluck("id", &locked)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	var last uint
+	result = tx.Model(&model.TNoteRevision{}).Where("note_id = ?", noteId).
+		Select("COALESCE(MAX(revision), 0)").Scan(&last)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return last + 1, nil
+}
+
+// noteRevisionRetention is NOTES_REVISION_RETENTION, the number of revisions
+// kept per note. 0, the default, keeps all of them.
+func noteRevisionRetention() int {
+	keep, err := strconv.Atoi(os.Getenv("NOTES_REVISION_RETENTION"))
+	if err != nil || keep < 0 {
+		return 0
+	}
+	return keep
+}
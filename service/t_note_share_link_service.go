@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/amsatrio/gin_notes/constant"
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/model/request"
+	"github.com/amsatrio/gin_notes/model/response"
+	"github.com/amsatrio/gin_notes/util"
+)
+
+const SHARE_LINK_PATH = "/p/"
+
+// TNoteShareLinkService keeps the public links of the notes in
+// t_note_share_link. The caller checks the user may share the note, see
+// ACCESS_SHARE.
+type TNoteShareLinkService interface {
+	// CreateTNoteShareLink returns a new link of a note with its slug, which
+	// is not stored and can not be read again.
+	CreateTNoteShareLink(context context.Context, noteId uint, link request.RequestNoteShareLink, mUser *model.MUser) (*response.ResponseNoteShareLink, error)
+	GetTNoteShareLinks(context context.Context, noteId uint) ([]model.TNoteShareLink, error)
+	RevokeTNoteShareLink(context context.Context, noteId uint, id uint) error
+	// OpenTNoteShareLink returns the note of slug and counts the access. A
+	// revoked or expired link and a deleted note are gorm.ErrRecordNotFound,
+	// a missing or wrong password is constant.ErrorSharePasswordInvalid.
+	OpenTNoteShareLink(context context.Context, slug string, password string) (*model.MNotes, error)
+}
+
+type TNoteShareLinkServiceImpl struct {
+	db *gorm.DB
+}
+
+func NewTNoteShareLinkServiceImpl(db *gorm.DB) TNoteShareLinkService {
+	return &TNoteShareLinkServiceImpl{
+		db: db,
+	}
+}
+
+func (s *TNoteShareLinkServiceImpl) CreateTNoteShareLink(context context.Context, noteId uint, link request.RequestNoteShareLink, mUser *model.MUser) (*response.ResponseNoteShareLink, error) {
+	if !link.ExpiredOn.IsZero() && link.ExpiredOn.Before(time.Now()) {
+		return nil, &util.FieldError{Messages: map[string]string{
+			"ExpiredOn": "should be in the future",
+		}}
+	}
+
+	slug, err := util.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+	tNoteShareLink := model.TNoteShareLink{
+		NoteId:    noteId,
+		SlugHash:  util.HashToken(slug),
+		ExpiredOn: link.ExpiredOn,
+		CreatedBy: mUser.Id,
+		CreatedOn: response.JSONTime{Time: time.Now()},
+	}
+	if link.Password != "" {
+		tNoteShareLink.PasswordHash, err = util.HashPassword(link.Password)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := s.db.Create(&tNoteShareLink).Error; err != nil {
+		return nil, err
+	}
+
+	util.Log("INFO", "service", "CreateTNoteShareLink", "note "+strconv.FormatUint(uint64(noteId), 10)+" published by link "+strconv.FormatUint(uint64(tNoteShareLink.Id), 10))
+	return &response.ResponseNoteShareLink{
+		Id:        tNoteShareLink.Id,
+		Slug:      slug,
+		Path:      SHARE_LINK_PATH + slug,
+		ExpiredOn: tNoteShareLink.ExpiredOn,
+	}, nil
+}
+
+func (s *TNoteShareLinkServiceImpl) GetTNoteShareLinks(context context.Context, noteId uint) ([]model.TNoteShareLink, error) {
+	links := []model.TNoteShareLink{}
+	result := s.db.Where("note_id = ?", noteId).Order("id").Find(&links)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for i := range links {
+		links[i].HasPassword = links[i].PasswordHash != ""
+	}
+	return links, nil
+}
+
+func (s *TNoteShareLinkServiceImpl) RevokeTNoteShareLink(context context.Context, noteId uint, id uint) error {
+	result := s.db.Model(&model.TNoteShareLink{}).
+		Where("id = ? AND note_id = ? AND revoked_on IS NULL", id, noteId).
+		Update("revoked_on", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s *TNoteShareLinkServiceImpl) OpenTNoteShareLink(context context.Context, slug string, password string) (*model.MNotes, error) {
+	tNoteShareLink := model.TNoteShareLink{}
+	result := s.db.Where("slug_hash = ?", util.HashToken(slug)).First(&tNoteShareLink)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if !tNoteShareLink.RevokedOn.IsZero() {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if !tNoteShareLink.ExpiredOn.IsZero() && time.Now().After(tNoteShareLink.ExpiredOn.Time) {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	mNotes, err := NewMNotesCrudService(s.db).Get(context, tNoteShareLink.NoteId)
+	if err != nil {
+		return nil, err
+	}
+
+	if tNoteShareLink.PasswordHash != "" {
+		if password == "" {
+			return nil, constant.ErrorSharePasswordInvalid
+		}
+		ok, _, err := util.VerifyPassword(tNoteShareLink.PasswordHash, password)
+		if err != nil {
+			util.Log("ERROR", "service", "OpenTNoteShareLink", "verify password error: "+err.Error())
+		}
+		if !ok {
+			return nil, constant.ErrorSharePasswordInvalid
+		}
+	}
+
+	// the access is counted in SQL so concurrent ones are all counted
+	result = s.db.Model(&model.TNoteShareLink{}).Where("id = ?", tNoteShareLink.Id).Updates(map[string]interface{}{
+		"access_count":     gorm.Expr("access_count + 1"),
+		"last_accessed_on": time.Now(),
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return mNotes, nil
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/model/request"
+	"github.com/amsatrio/gin_notes/model/response"
+	"github.com/amsatrio/gin_notes/util"
+)
+
+const (
+	SHARE_VIEW  = "view"
+	SHARE_EDIT  = "edit"
+	SHARE_OWNER = "owner"
+)
+
+// shareRanks orders the share permissions, a permission grants the ones of a
+// lower rank.
+var shareRanks = map[string]int{
+	SHARE_VIEW:  1,
+	SHARE_EDIT:  2,
+	SHARE_OWNER: 3,
+}
+
+// shareRequired is the permission each AccessAction needs on a shared note.
+var shareRequired = map[AccessAction]string{
+	ACCESS_READ:   SHARE_VIEW,
+	ACCESS_UPDATE: SHARE_EDIT,
+	ACCESS_DELETE: SHARE_OWNER,
+	ACCESS_SHARE:  SHARE_OWNER,
+}
+
+// TNoteShareService keeps the grants of the notes in t_note_share. The
+// caller checks the user may share the note, see ACCESS_SHARE.
+type TNoteShareService interface {
+	GetTNoteShares(context context.Context, noteId uint) ([]model.TNoteShare, error)
+	// GrantTNoteShare grants a note to a user or a role, the permission of
+	// an existing grant is replaced.
+	GrantTNoteShare(context context.Context, noteId uint, grant request.RequestNoteShare, mUser *model.MUser) (*model.TNoteShare, error)
+	RevokeTNoteShare(context context.Context, noteId uint, id uint) error
+}
+
+type TNoteShareServiceImpl struct {
+	db *gorm.DB
+}
+
+func NewTNoteShareServiceImpl(db *gorm.DB) TNoteShareService {
+	return &TNoteShareServiceImpl{
+		db: db,
+	}
+}
+
+func (s *TNoteShareServiceImpl) GetTNoteShares(context context.Context, noteId uint) ([]model.TNoteShare, error) {
+	shares := []model.TNoteShare{}
+	result := s.db.Where("note_id = ?", noteId).Order("id").Find(&shares)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return shares, nil
+}
+
+func (s *TNoteShareServiceImpl) GrantTNoteShare(context context.Context, noteId uint, grant request.RequestNoteShare, mUser *model.MUser) (*model.TNoteShare, error) {
+	if (grant.UserId == 0) == (grant.RoleId == 0) {
+		return nil, &util.FieldError{Messages: map[string]string{
+			"UserId": "either UserId or RoleId should be set",
+		}}
+	}
+	if grant.UserId != 0 {
+		if _, err := NewMUserCrudService(s.db).Get(context, grant.UserId); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, &util.FieldError{Messages: map[string]string{"UserId": "should be the id of an existing user"}}
+			}
+			return nil, err
+		}
+	}
+	if grant.RoleId != 0 {
+		if _, err := NewMRoleCrudService(s.db).Get(context, grant.RoleId); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, &util.FieldError{Messages: map[string]string{"RoleId": "should be the id of an existing role"}}
+			}
+			return nil, err
+		}
+	}
+
+	now := response.JSONTime{Time: time.Now()}
+	share := model.TNoteShare{}
+	result := s.db.Where("note_id = ? AND user_id = ? AND role_id = ?", noteId, grant.UserId, grant.RoleId).First(&share)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		share = model.TNoteShare{
+			NoteId:     noteId,
+			UserId:     grant.UserId,
+			RoleId:     grant.RoleId,
+			Permission: grant.Permission,
+			CreatedBy:  mUser.Id,
+			CreatedOn:  now,
+		}
+		result = s.db.Create(&share)
+	} else if result.Error == nil {
+		share.Permission = grant.Permission
+		share.ModifiedBy = mUser.Id
+		share.ModifiedOn = now
+		result = s.db.Model(&share).Select("Permission", "ModifiedBy", "ModifiedOn").Updates(&share)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	util.Log("INFO", "service", "GrantTNoteShare", "note "+strconv.FormatUint(uint64(noteId), 10)+" shared with "+grant.Permission+" permission")
+	return &share, nil
+}
+
+func (s *TNoteShareServiceImpl) RevokeTNoteShare(context context.Context, noteId uint, id uint) error {
+	result := s.db.Where("note_id = ?", noteId).Delete(&model.TNoteShare{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// noteShared is the CrudConfig.Shared of MNotes, the best grant of mUser,
+// directly or through its role, has to rank as high as the one action
+// needs.
+func noteShared(context context.Context, db *gorm.DB, mNotes *model.MNotes, action AccessAction, mUser *model.MUser) (bool, error) {
+	var permissions []string
+	result := sharesOf(db.Model(&model.TNoteShare{}), mUser).
+		Where("note_id = ?", mNotes.Id).Pluck("permission", &permissions)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	required := shareRanks[shareRequired[action]]
+	for _, permission := range permissions {
+		if rank, ok := shareRanks[permission]; ok && required > 0 && rank >= required {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// noteSharedScope is the CrudConfig.SharedScope of MNotes.
+func noteSharedScope(db *gorm.DB, mUser *model.MUser) *gorm.DB {
+	return db.Where("id IN (?)", sharesOf(db.Session(&gorm.Session{NewDB: true}).Model(&model.TNoteShare{}), mUser).Select("note_id"))
+}
+
+// sharesOf scopes db to the grants of mUser and of its role.
+func sharesOf(db *gorm.DB, mUser *model.MUser) *gorm.DB {
+	if mUser.RoleId == 0 {
+		return db.Where("user_id = ?", mUser.Id)
+	}
+	return db.Where("user_id = ? OR role_id = ?", mUser.Id, mUser.RoleId)
+}
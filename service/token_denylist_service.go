@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/amsatrio/gin_notes/initializer"
+	"github.com/amsatrio/gin_notes/util"
+)
+
+const (
+	DENYLIST_TOKEN_KEY = "denylist:token:"
+	DENYLIST_USER_KEY  = "denylist:user:"
+)
+
+// TokenDenylistService keeps revoked access tokens in Redis. A key lives as
+// long as the tokens it revokes, so the list never grows past the tokens
+// still valid. Nothing is kept when REDIS_ENABLE is false.
+type TokenDenylistService interface {
+	// Deny revokes the token tokenId until expiredOn.
+	Deny(context context.Context, tokenId string, expiredOn time.Time) error
+	// DenyUser revokes every token of username issued before now.
+	DenyUser(context context.Context, username string) error
+	// IsDenied reports whether the token tokenId of username issued at
+	// issuedAt is revoked.
+	IsDenied(context context.Context, username string, tokenId string, issuedAt time.Time) (bool, error)
+}
+
+type TokenDenylistServiceImpl struct {
+	rdb *redis.Client
+}
+
+func NewTokenDenylistServiceImpl(rdb *redis.Client) TokenDenylistService {
+	return &TokenDenylistServiceImpl{
+		rdb: rdb,
+	}
+}
+
+func (s *TokenDenylistServiceImpl) Deny(context context.Context, tokenId string, expiredOn time.Time) error {
+	if !denylistEnabled() || tokenId == "" {
+		return nil
+	}
+	ttl := time.Until(expiredOn)
+	if ttl <= 0 {
+		return nil
+	}
+	return s.rdb.Set(context, DENYLIST_TOKEN_KEY+tokenId, 1, ttl).Err()
+}
+
+func (s *TokenDenylistServiceImpl) DenyUser(context context.Context, username string) error {
+	if !denylistEnabled() || username == "" {
+		return nil
+	}
+	// iat has a second precision, a token of the current second stays valid
+	// so a login right after the change is not revoked
+	watermark := time.Now().Unix()
+	err := s.rdb.Set(context, DENYLIST_USER_KEY+username, watermark, util.JwtMainTokenLifetime()).Err()
+	if err != nil {
+		return err
+	}
+	util.Log("INFO", "service", "TokenDenylistService", "tokens of user "+username+" issued before "+strconv.FormatInt(watermark, 10)+" revoked")
+	return nil
+}
+
+func (s *TokenDenylistServiceImpl) IsDenied(context context.Context, username string, tokenId string, issuedAt time.Time) (bool, error) {
+	if !denylistEnabled() {
+		return false, nil
+	}
+
+	if tokenId != "" {
+		count, err := s.rdb.Exists(context, DENYLIST_TOKEN_KEY+tokenId).Result()
+		if err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+
+	watermark, err := s.rdb.Get(context, DENYLIST_USER_KEY+username).Int64()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return issuedAt.Unix() < watermark, nil
+}
+
+// denyUserTokens revokes the access tokens username got before a password
+// change.
+func denyUserTokens(context context.Context, username string) error {
+	return NewTokenDenylistServiceImpl(initializer.RDB).DenyUser(context, username)
+}
+
+func denylistEnabled() bool {
+	return os.Getenv("REDIS_ENABLE") != "false"
+}
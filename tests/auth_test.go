@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/cookie"
+	"github.com/gin-gonic/gin"
+
+	"github.com/amsatrio/gin_notes/initializer"
+	"github.com/amsatrio/gin_notes/middleware"
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/model/response"
+	"github.com/amsatrio/gin_notes/service"
+)
+
+const authPassword = "Password1"
+
+// authRoles are the roles of the access tests and the permissions they are
+// granted, nil grants every permission. Their level 0 keeps them out of the
+// roles inherited in m_role_test.go.
+var authRoles = []struct {
+	id          uint
+	code        string
+	permissions []string
+}{
+	{200, "TEST_ALL", nil},
+	{201, "TEST_NOTES", []string{"notes:read", "notes:write"}},
+}
+
+// authUsers are the users of the access tests and their role.
+var authUsers = map[string]struct{ id, roleId uint }{
+	"all@example.com":   {200, 200},
+	"alice@example.com": {201, 201},
+	"bob@example.com":   {202, 201},
+	"carol@example.com": {203, 201},
+}
+
+var seedAuthUsersOnce sync.Once
+
+func seedAuthUsers() {
+	seedAuthUsersOnce.Do(func() {
+		now := response.JSONTime{Time: time.Now()}
+		for _, authRole := range authRoles {
+			mRole := model.MRole{Id: authRole.id, Code: authRole.code, Level: 0, CreatedBy: 1, CreatedOn: now}
+			if err := initializer.DB.FirstOrCreate(&mRole).Error; err != nil {
+				log.Fatal("Failed to seed users: " + err.Error())
+			}
+			query := initializer.DB
+			if authRole.permissions != nil {
+				query = query.Where("code IN ?", authRole.permissions)
+			}
+			var permissions []model.MPermission
+			if err := query.Find(&permissions).Error; err != nil {
+				log.Fatal("Failed to seed users: " + err.Error())
+			}
+			for _, mPermission := range permissions {
+				grant := model.MRolePermission{RoleId: mRole.Id, PermissionId: mPermission.Id, CreatedBy: 1, CreatedOn: now}
+				if err := initializer.DB.FirstOrCreate(&grant).Error; err != nil {
+					log.Fatal("Failed to seed users: " + err.Error())
+				}
+			}
+		}
+
+		mUserService := service.NewMUserServiceImpl(initializer.DB)
+		for email, authUser := range authUsers {
+			mUser := model.MUser{Id: authUser.id, RoleId: authUser.roleId, Email: email, Password: authPassword}
+			if err := mUserService.CreateMUser(context.Background(), &mUser, &model.MUser{Id: 1}); err != nil {
+				log.Fatal("Failed to seed users: " + err.Error())
+			}
+		}
+	})
+}
+
+// createUser adds a user of the role TEST_NOTES.
+func createUser(t *testing.T, id uint, email string) {
+	mUser := model.MUser{Id: id, RoleId: 201, Email: email, Password: authPassword}
+	if err := service.NewMUserServiceImpl(initializer.DB).CreateMUser(context.Background(), &mUser, &model.MUser{Id: 1}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// enableAuth turns the token checks on for the test. The denylist is off,
+// the tests run without Redis.
+func enableAuth(t *testing.T) {
+	t.Setenv("AUTH_JWT_ENABLE", "true")
+	t.Setenv("AUTH_JWT_TOKEN_SECRET", "secret")
+	t.Setenv("AUTH_JWT_TOKEN_EXPIRED_MS", "600000")
+	t.Setenv("REDIS_ENABLE", "false")
+}
+
+// SetUpAuthRouter is SetUpRouter with the session and the token middlewares
+// of main.
+func SetUpAuthRouter() *gin.Engine {
+	Initialize()
+	seedAuthUsers()
+	r := gin.Default()
+	r.Use(middleware.CORSGinMiddleware())
+	r.Use(middleware.LoggerMiddleware)
+	r.Use(middleware.CompressMiddleware())
+	r.Use(middleware.CustomErrorApiMiddleware())
+	r.Use(sessions.Sessions("mysession", cookie.NewStore([]byte("secret"))))
+	r.Use(middleware.JwtMiddleware())
+	AppRoutes(r)
+
+	return r
+}
+
+// authSession sends the requests of a logged in user.
+type authSession struct {
+	router       *gin.Engine
+	token        string
+	refreshToken string
+	cookies      []*http.Cookie
+}
+
+func login(t *testing.T, router *gin.Engine, email string) *authSession {
+	s := &authSession{router: router}
+	w := s.do("POST", "/v1/auth/login", `{"username":"`+email+`","password":"`+authPassword+`"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("login of %s: %d %s", email, w.Code, w.Body.String())
+	}
+	body := struct {
+		Data response.ResponseAuth `json:"data"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	s.token = body.Data.Token
+	s.refreshToken = body.Data.RefreshToken
+	s.cookies = w.Result().Cookies()
+	return s
+}
+
+func (s *authSession) do(method string, path string, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	for _, c := range s.cookies {
+		req.AddCookie(c)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
@@ -0,0 +1,44 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+)
+
+func TestColumnsOutsideTheModelAreRejected(t *testing.T) {
+	router := SetUpFilterRouter()
+
+	code, _ := getPage(router, "/v1/m_biodata", filterQuery(`{"id":"id = id OR 1","value":"1"}`))
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = getPage(router, "/v1/m_biodata", sortQuery(`[{"id":"(SELECT 1)"}]`))
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// the go field and the column name resolve as the json name does
+	code, ids := getPage(router, "/v1/m_biodata", filterQuery(`{"id":"Fullname","value":"axb","matchMode":"EQUALS"}`))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{1004}, ids)
+	code, ids = getPage(router, "/v1/m_biodata", filterQuery(`{"id":"created_on","value":"2024-01-03 00:00:00","matchMode":"EQUALS","dataType":"DATE"}`))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{1003}, ids)
+}
+
+func TestExcludedColumnsAreNotQueried(t *testing.T) {
+	router := SetUpFilterRouter()
+	seedAuthUsers()
+
+	code, _ := getPage(router, "/v1/m_user", url.Values{"_filter": {`[{"id":"password","value":"argon2id"}]`}})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = getPage(router, "/v1/m_user", url.Values{"_sort": {`[{"id":"Password"}]`}})
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// the global search skips the password hashes
+	code, ids := getPage(router, "/v1/m_user", url.Values{"_q": {"argon2id"}})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{}, ids)
+	code, ids = getPage(router, "/v1/m_biodata", url.Values{"_q": {"DONE"}, "_filter": {"[" + filterRange + "]"}, "_sort": {`[{"id":"id"}]`}})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{1001, 1002}, ids)
+}
@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+
+	"github.com/amsatrio/gin_notes/initializer"
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/service"
+)
+
+func TestCrudServiceUpdatesOnlyUpdatableFields(t *testing.T) {
+	Initialize()
+	crud := service.NewMBiodataCrudService(initializer.DB)
+	ctx := context.Background()
+
+	mBiodata := model.MBiodata{Id: 1101, Fullname: "created"}
+	if err := crud.Create(ctx, &mBiodata, &model.MUser{Id: 7}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint(7), mBiodata.CreatedBy)
+	assert.Equal(t, false, mBiodata.CreatedOn.IsZero())
+
+	assert.NotEqual(t, nil, crud.Create(ctx, &model.MBiodata{Id: 1101}, &model.MUser{Id: 7}))
+
+	update := model.MBiodata{Id: 1101, Fullname: "updated", CreatedBy: 99}
+	if err := crud.Update(ctx, &update, &model.MUser{Id: 8}); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := crud.Get(ctx, 1101)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "updated", saved.Fullname)
+	assert.Equal(t, uint(7), saved.CreatedBy)
+	assert.Equal(t, uint(8), saved.ModifiedBy)
+
+	assert.NotEqual(t, nil, crud.Update(ctx, &model.MBiodata{Id: 1199}, &model.MUser{Id: 8}))
+}
+
+func TestCrudServiceDeletes(t *testing.T) {
+	Initialize()
+	crud := service.NewMBiodataCrudService(initializer.DB)
+	ctx := context.Background()
+
+	if err := crud.Create(ctx, &model.MBiodata{Id: 1102, Fullname: "deleted"}, &model.MUser{Id: 7}); err != nil {
+		t.Fatal(err)
+	}
+	if err := crud.Delete(ctx, 1102, &model.MUser{Id: 7}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := crud.Get(ctx, 1102)
+	assert.NotEqual(t, nil, err)
+	assert.NotEqual(t, nil, crud.Delete(ctx, 1102, &model.MUser{Id: 7}))
+}
@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+)
+
+func cursorQuery(params ...string) url.Values {
+	query := url.Values{"_filter": {"[" + filterRange + "]"}, "_sort": {`[{"id":"fullname"}]`}, "_size": {"2"}, "_cursor": {"true"}}
+	for i := 0; i+1 < len(params); i += 2 {
+		query.Set(params[i], params[i+1])
+	}
+	return query
+}
+
+func TestCursorPagesRoundTrip(t *testing.T) {
+	router := SetUpFilterRouter()
+
+	code, first := getPageData(router, "/v1/m_biodata", cursorQuery())
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{1002, 1001}, first.ids())
+	assert.Equal(t, true, first.First)
+	assert.Equal(t, false, first.Last)
+
+	code, second := getPageData(router, "/v1/m_biodata", cursorQuery("_after", first.NextCursor))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{1003, 1004}, second.ids())
+	assert.Equal(t, true, second.Last)
+
+	code, back := getPageData(router, "/v1/m_biodata", cursorQuery("_before", second.PrevCursor))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, first.ids(), back.ids())
+	assert.Equal(t, true, back.First)
+}
+
+func TestCursorRejectsTamperedToken(t *testing.T) {
+	router := SetUpFilterRouter()
+
+	_, first := getPageData(router, "/v1/m_biodata", cursorQuery())
+
+	code, _ := getPageData(router, "/v1/m_biodata", cursorQuery("_after", "not a cursor"))
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// a cursor of another sort
+	code, _ = getPageData(router, "/v1/m_biodata", cursorQuery("_after", first.NextCursor, "_sort", `[{"id":"id"}]`))
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// values that do not fit the sort keys
+	data, _ := base64.RawURLEncoding.DecodeString(first.NextCursor)
+	token := map[string]interface{}{}
+	_ = json.Unmarshal(data, &token)
+	token["v"] = []interface{}{"100 done", "not an id"}
+	data, _ = json.Marshal(token)
+	code, _ = getPageData(router, "/v1/m_biodata", cursorQuery("_after", base64.RawURLEncoding.EncodeToString(data)))
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = getPageData(router, "/v1/m_biodata", cursorQuery("_after", first.NextCursor, "_before", first.NextCursor))
+	assert.Equal(t, http.StatusBadRequest, code)
+}
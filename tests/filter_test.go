@@ -0,0 +1,206 @@
+package tests
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"gorm.io/gorm"
+
+	"github.com/amsatrio/gin_notes/initializer"
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/model/response"
+)
+
+// filterBiodata are the rows of the filter tests, the other tests keep away
+// from ids 1001-1005. The MobilePhone of 1005 is NULL.
+var filterBiodata = []model.MBiodata{
+	{Id: 1001, Fullname: "100% done", CreatedOn: filterDate("2024-01-01 10:00:00")},
+	{Id: 1002, Fullname: "100 done", CreatedOn: filterDate("2024-01-02 15:00:00")},
+	{Id: 1003, Fullname: "a_b", CreatedOn: filterDate("2024-01-03 00:00:00")},
+	{Id: 1004, Fullname: "axb", CreatedOn: filterDate("2024-01-04 12:00:00")},
+	{Id: 1005, Fullname: "null phone", CreatedOn: filterDate("2024-01-05 12:00:00")},
+}
+
+// filterRange keeps a filter to the rows of filterBiodata.
+const filterRange = `{"id":"id","value":[1001,1004],"matchMode":"BETWEEN","dataType":"NUMBER"}`
+
+var seedFilterBiodataOnce sync.Once
+
+func filterDate(value string) response.JSONTime {
+	date, err := time.ParseInLocation(response.JSONTimeLayout, value, time.Local)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return response.JSONTime{Time: date}
+}
+
+func SetUpFilterRouter() *gin.Engine {
+	router := SetUpRouter()
+	seedFilterBiodataOnce.Do(func() {
+		for _, mBiodata := range filterBiodata {
+			mBiodata.CreatedBy = 1
+			if err := initializer.DB.FirstOrCreate(&mBiodata).Error; err != nil {
+				log.Fatal("Failed to seed biodata: " + err.Error())
+			}
+		}
+		if err := initializer.DB.Model(&model.MBiodata{Id: 1005}).Update("mobile_phone", gorm.Expr("NULL")).Error; err != nil {
+			log.Fatal("Failed to seed biodata: " + err.Error())
+		}
+	})
+	return router
+}
+
+// pageData is the part of response.Page the tests look at.
+type pageData struct {
+	Content []struct {
+		Id uint `json:"id"`
+	} `json:"content"`
+	TotalPages    int64  `json:"totalPages"`
+	TotalElements int64  `json:"totalElements"`
+	Last          bool   `json:"last"`
+	First         bool   `json:"first"`
+	NextCursor    string `json:"nextCursor"`
+	PrevCursor    string `json:"prevCursor"`
+}
+
+func (p pageData) ids() []uint {
+	ids := []uint{}
+	for _, content := range p.Content {
+		ids = append(ids, content.Id)
+	}
+	return ids
+}
+
+// getPageData requests path with query and returns the status and the page.
+func getPageData(router *gin.Engine, path string, query url.Values) (int, pageData) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path+"?"+query.Encode(), nil)
+	router.ServeHTTP(w, req)
+
+	body := struct {
+		Data pageData `json:"data"`
+	}{}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	return w.Code, body.Data
+}
+
+// getPage requests path with query and returns the status and the ids of the
+// page content.
+func getPage(router *gin.Engine, path string, query url.Values) (int, []uint) {
+	code, page := getPageData(router, path, query)
+	return code, page.ids()
+}
+
+func filterQuery(filters ...string) url.Values {
+	filter := "[" + filterRange
+	for _, f := range filters {
+		filter += "," + f
+	}
+	return url.Values{"_filter": {filter + "]"}, "_sort": {`[{"id":"id"}]`}}
+}
+
+func TestFilterContainsMatchesWildcardsLiterally(t *testing.T) {
+	router := SetUpFilterRouter()
+
+	code, ids := getPage(router, "/v1/m_biodata", filterQuery(`{"id":"fullname","value":"0%","matchMode":"CONTAINS"}`))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{1001}, ids)
+
+	code, ids = getPage(router, "/v1/m_biodata", filterQuery(`{"id":"fullname","value":"a_b","matchMode":"CONTAINS"}`))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{1003}, ids)
+}
+
+func TestFilterMatchModes(t *testing.T) {
+	router := SetUpFilterRouter()
+
+	cases := map[string][]uint{
+		`{"id":"fullname","value":"axb","matchMode":"EQUALS"}`:                                    {1004},
+		`{"id":"fullname","value":"axb","matchMode":"NOT"}`:                                       {1001, 1002, 1003},
+		`{"id":"id","value":1002,"matchMode":"LESS_THAN","dataType":"NUMBER"}`:                    {1001},
+		`{"id":"id","value":1002,"matchMode":"GREATER_THAN","dataType":"NUMBER"}`:                 {1003, 1004},
+		`{"id":"createdOn","value":"2024-01-03 00:00:00","matchMode":"EQUALS","dataType":"DATE"}`: {1003},
+	}
+	for filter, want := range cases {
+		code, ids := getPage(router, "/v1/m_biodata", filterQuery(filter))
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, want, ids)
+	}
+}
+
+func TestFilterNotMatchesNull(t *testing.T) {
+	router := SetUpFilterRouter()
+
+	query := url.Values{
+		"_filter": {`[{"id":"id","value":[1004,1005],"matchMode":"BETWEEN","dataType":"NUMBER"},{"id":"mobilePhone","value":"0800","matchMode":"NOT"}]`},
+		"_sort":   {`[{"id":"id"}]`},
+	}
+	code, ids := getPage(router, "/v1/m_biodata", query)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{1004, 1005}, ids)
+}
+
+func TestFilterDateBetweenIncludesLastDay(t *testing.T) {
+	router := SetUpFilterRouter()
+
+	code, ids := getPage(router, "/v1/m_biodata", filterQuery(`{"id":"createdOn","value":["2024-01-01","2024-01-02"],"matchMode":"BETWEEN","dataType":"DATE"}`))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{1001, 1002}, ids)
+
+	code, ids = getPage(router, "/v1/m_biodata", filterQuery(`{"id":"createdOn","value":[null,"2024-01-03"],"matchMode":"BETWEEN","dataType":"DATE"}`))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{1001, 1002, 1003}, ids)
+
+	// a bound with a time is kept as it is
+	code, ids = getPage(router, "/v1/m_biodata", filterQuery(`{"id":"createdOn","value":["2024-01-01 00:00:00","2024-01-03 00:00:00"],"matchMode":"BETWEEN","dataType":"DATE"}`))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{1001, 1002, 1003}, ids)
+}
+
+func TestFilterRejectsUnsupportedMatchMode(t *testing.T) {
+	router := SetUpFilterRouter()
+
+	code, _ := getPage(router, "/v1/m_biodata", filterQuery(`{"id":"id","value":1,"matchMode":"CONTAINS","dataType":"NUMBER"}`))
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = getPage(router, "/v1/m_biodata", filterQuery(`{"id":"id","value":"one","matchMode":"EQUALS","dataType":"NUMBER"}`))
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestFilterGroupsJoinWithTheirMode(t *testing.T) {
+	router := SetUpFilterRouter()
+
+	// (1001 OR 1004) AND fullname CONTAINS "done"
+	code, ids := getPage(router, "/v1/m_biodata", filterQuery(
+		`{"mode":"OR","filters":[{"id":"id","value":1001,"dataType":"NUMBER"},{"id":"id","value":1004,"dataType":"NUMBER"}]}`,
+		`{"id":"fullname","value":"done"}`))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{1001}, ids)
+
+	// a condition joins the ones before it with its own mode
+	code, ids = getPage(router, "/v1/m_biodata", filterQuery(
+		`{"mode":"AND","filters":[{"id":"fullname","value":"a_b","matchMode":"EQUALS"},{"id":"fullname","value":"axb","matchMode":"EQUALS","mode":"OR"}]}`))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{1003, 1004}, ids)
+}
+
+func TestFilterRejectsDeepGroups(t *testing.T) {
+	router := SetUpFilterRouter()
+
+	filter := `{"id":"id","value":1001,"dataType":"NUMBER"}`
+	for i := 0; i < 6; i++ {
+		filter = `{"mode":"AND","filters":[` + filter + `]}`
+	}
+	code, _ := getPage(router, "/v1/m_biodata", filterQuery(filter))
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = getPage(router, "/v1/m_biodata", filterQuery(`{"mode":"XOR","filters":[{"id":"id","value":1001,"dataType":"NUMBER"}]}`))
+	assert.Equal(t, http.StatusBadRequest, code)
+}
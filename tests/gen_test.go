@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+)
+
+// copyModule copies the sources of the module into a temporary directory.
+func copyModule(t *testing.T) string {
+	dir := t.TempDir()
+	for _, name := range []string{"go.mod", "go.sum", "main.go"} {
+		data, err := os.ReadFile(filepath.Join("..", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"cmd", "constant", "controller", "docs", "initializer", "middleware", "migrate", "migration", "model", "route", "service", "util"} {
+		if err := os.CopyFS(filepath.Join(dir, name), os.DirFS(filepath.Join("..", name))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func runGo(t *testing.T, dir string, args ...string) (string, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=readonly")
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func TestGenScaffoldsABuildingEntity(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a copy of the module")
+	}
+	dir := copyModule(t)
+
+	out, err := runGo(t, dir, "run", "./cmd/gen", "-table", "m_category", "-fields", "name:string:100,level:int,active:bool")
+	if err != nil {
+		t.Fatal(out)
+	}
+	for _, path := range []string{"model/m_category.go", "service/m_category_service.go", "controller/m_category_api_gen.go"} {
+		_, err := os.Stat(filepath.Join(dir, path))
+		assert.Equal(t, nil, err)
+	}
+
+	// a second run keeps a single route
+	if out, err := runGo(t, dir, "run", "./cmd/gen", "-table", "m_category", "-fields", "name:string:100"); err != nil {
+		t.Fatal(out)
+	}
+	routes, _ := os.ReadFile(filepath.Join(dir, "route", "routes.go"))
+	assert.Equal(t, 1, strings.Count(string(routes), `controller.RegisterResource(v1, "m_category", controller.MCategoryResource)`))
+
+	if out, err := runGo(t, dir, "build", "./..."); err != nil {
+		t.Fatal(out)
+	}
+}
+
+func TestGenRejectsInvalidEntities(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a copy of the module")
+	}
+	dir := copyModule(t)
+
+	_, err := runGo(t, dir, "run", "./cmd/gen", "-table", "MCategory", "-fields", "name:string")
+	assert.NotEqual(t, nil, err)
+	_, err = runGo(t, dir, "run", "./cmd/gen", "-table", "m_category", "-fields", "name:money")
+	assert.NotEqual(t, nil, err)
+
+	// a hand written controller is kept
+	_, err = runGo(t, dir, "run", "./cmd/gen", "-table", "m_notes", "-fields", "title:string")
+	assert.NotEqual(t, nil, err)
+}
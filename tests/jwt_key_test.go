@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/amsatrio/gin_notes/util"
+)
+
+// jwtKeyFiles writes a PEM private key and its public key to dir and returns
+// their paths.
+func jwtKeyFiles(t *testing.T, dir string, name string, private interface{}, public interface{}) (string, string) {
+	privateDer, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDer, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privatePath := filepath.Join(dir, name+".pem")
+	publicPath := filepath.Join(dir, name+".pub.pem")
+	if err := os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDer}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return privatePath, publicPath
+}
+
+// loadJwtKeyring loads the keyring of algorithm, privatePath and the public
+// key files.
+func loadJwtKeyring(t *testing.T, algorithm string, privatePath string, publicPaths string) *util.JwtKeyring {
+	t.Setenv("AUTH_JWT_ALGORITHM", algorithm)
+	t.Setenv("AUTH_JWT_PRIVATE_KEY_FILE", privatePath)
+	t.Setenv("AUTH_JWT_PUBLIC_KEY_FILES", publicPaths)
+	keyring, err := util.LoadJwtKeyring()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keyring
+}
+
+func jwtKeyClaims() jwt.MapClaims {
+	return jwt.MapClaims{"sub": "key@example.com", "exp": time.Now().Add(time.Minute).Unix()}
+}
+
+func TestJwtKeyRejectsAlgorithmMismatch(t *testing.T) {
+	dir := t.TempDir()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPath, rsaPublicPath := jwtKeyFiles(t, dir, "rsa", rsaKey, &rsaKey.PublicKey)
+	edPath, edPublicPath := jwtKeyFiles(t, dir, "ed", edPrivate, edPublic)
+
+	rsaKeyring := loadJwtKeyring(t, "RS256", rsaPath, edPublicPath)
+	edKeyring := loadJwtKeyring(t, "EdDSA", edPath, "")
+
+	token, err := rsaKeyring.Sign(jwtKeyClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = jwt.Parse(token, rsaKeyring.Keyfunc)
+	assert.Equal(t, nil, err)
+
+	// an HS256 token keyed with the published RSA key
+	rsaPublicPem, _ := os.ReadFile(rsaPublicPath)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwtKeyClaims())
+	forged.Header["kid"] = rsaKeyring.Signing.Id
+	forgedToken, err := forged.SignedString(rsaPublicPem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = jwt.Parse(forgedToken, rsaKeyring.Keyfunc)
+	assert.NotEqual(t, nil, err)
+
+	// an RS256 token naming the Ed25519 key
+	mismatch := jwt.NewWithClaims(jwt.SigningMethodRS256, jwtKeyClaims())
+	mismatch.Header["kid"] = edKeyring.Signing.Id
+	mismatchToken, err := mismatch.SignedString(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = jwt.Parse(mismatchToken, rsaKeyring.Keyfunc)
+	assert.NotEqual(t, nil, err)
+
+	// a token of a key the keyring does not know
+	_, err = jwt.Parse(token, edKeyring.Keyfunc)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestJwtKeyAcceptsPreviousKeyAfterRotation(t *testing.T) {
+	dir := t.TempDir()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPath, rsaPublicPath := jwtKeyFiles(t, dir, "rsa", rsaKey, &rsaKey.PublicKey)
+	edPath, _ := jwtKeyFiles(t, dir, "ed", edPrivate, edPublic)
+
+	previous := loadJwtKeyring(t, "RS256", rsaPath, "")
+	token, err := previous.Sign(jwtKeyClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := loadJwtKeyring(t, "EdDSA", edPath, rsaPublicPath)
+	_, err = jwt.Parse(token, rotated.Keyfunc)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(rotated.Jwks()["keys"].([]map[string]string)))
+
+	// the algorithm has to be the one of the private key
+	t.Setenv("AUTH_JWT_ALGORITHM", "RS256")
+	_, err = util.LoadJwtKeyring()
+	assert.NotEqual(t, nil, err)
+}
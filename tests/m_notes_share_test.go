@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+
+	"github.com/amsatrio/gin_notes/model"
+)
+
+func TestMNotesShareRanks(t *testing.T) {
+	enableAuth(t)
+	router := SetUpAuthRouter()
+	alice := login(t, router, "alice@example.com")
+	bob := login(t, router, "bob@example.com")
+	carol := login(t, router, "carol@example.com")
+
+	w := alice.do("POST", "/v1/m_notes", `{"id":2302,"title":"ranked","content":"c"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// there is no permission between view and edit
+	assert.Equal(t, http.StatusBadRequest, alice.do("POST", "/v1/m_notes/2302/shares", `{"userId":202,"permission":"comment"}`).Code)
+
+	// view reads only
+	w = alice.do("POST", "/v1/m_notes/2302/shares", `{"userId":202,"permission":"view"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusOK, bob.do("GET", "/v1/m_notes/2302", "").Code)
+	assert.Equal(t, http.StatusForbidden, bob.do("PUT", "/v1/m_notes/2302", `{"id":2302,"title":"edited","content":"c"}`).Code)
+	assert.Equal(t, http.StatusForbidden, bob.do("POST", "/v1/m_notes/2302/shares", `{"userId":203,"permission":"view"}`).Code)
+
+	_, ids := bob.page("/v1/m_notes", url.Values{"_view": {"shared"}, "_filter": {`[{"id":"id","value":2302,"dataType":"NUMBER"}]`}})
+	assert.Equal(t, []uint{2302}, ids)
+
+	// a new grant replaces the one of the same user, edit updates
+	w = alice.do("POST", "/v1/m_notes/2302/shares", `{"userId":202,"permission":"edit"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusOK, bob.do("PUT", "/v1/m_notes/2302", `{"id":2302,"title":"edited","content":"c"}`).Code)
+	assert.Equal(t, http.StatusForbidden, bob.do("DELETE", "/v1/m_notes/2302", "").Code)
+
+	// a role grant reaches every user of the role
+	w = alice.do("POST", "/v1/m_notes/2302/shares", `{"roleId":201,"permission":"view"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusOK, carol.do("GET", "/v1/m_notes/2302", "").Code)
+	assert.Equal(t, http.StatusForbidden, carol.do("PUT", "/v1/m_notes/2302", `{"id":2302,"title":"carol","content":"c"}`).Code)
+
+	w = alice.do("GET", "/v1/m_notes/2302/shares", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	shares := struct {
+		Data []model.TNoteShare `json:"data"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &shares); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, len(shares.Data))
+
+	for _, share := range shares.Data {
+		if share.RoleId != 201 {
+			continue
+		}
+		w = alice.do("DELETE", "/v1/m_notes/2302/shares/"+strconv.FormatUint(uint64(share.Id), 10), "")
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	assert.Equal(t, http.StatusForbidden, carol.do("GET", "/v1/m_notes/2302", "").Code)
+
+	// owner shares and deletes
+	w = alice.do("POST", "/v1/m_notes/2302/shares", `{"userId":202,"permission":"owner"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusOK, bob.do("POST", "/v1/m_notes/2302/shares", `{"userId":203,"permission":"view"}`).Code)
+	assert.Equal(t, http.StatusOK, bob.do("DELETE", "/v1/m_notes/2302", "").Code)
+}
+
+func TestMNotesShareEndsWithItsNote(t *testing.T) {
+	enableAuth(t)
+	router := SetUpAuthRouter()
+	alice := login(t, router, "alice@example.com")
+	bob := login(t, router, "bob@example.com")
+
+	w := alice.do("POST", "/v1/m_notes", `{"id":2301,"title":"shared","content":"c"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = alice.do("POST", "/v1/m_notes/2301/shares", `{"userId":202,"permission":"edit"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = bob.do("GET", "/v1/m_notes/2301", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = alice.do("DELETE", "/v1/m_notes/2301", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// a new note of the same id is not shared
+	w = alice.do("POST", "/v1/m_notes", `{"id":2301,"title":"private","content":"c"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = bob.do("GET", "/v1/m_notes/2301", "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = bob.do("PUT", "/v1/m_notes/2301", `{"id":2301,"title":"edited","content":"c"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+)
+
+func TestMNotesScopedToOwner(t *testing.T) {
+	enableAuth(t)
+	router := SetUpAuthRouter()
+	alice := login(t, router, "alice@example.com")
+	bob := login(t, router, "bob@example.com")
+	all := login(t, router, "all@example.com")
+
+	w := alice.do("POST", "/v1/m_notes", `{"id":2201,"title":"mine","content":"c"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	query := url.Values{"_filter": {`[{"id":"id","value":2201,"dataType":"NUMBER"}]`}}
+	_, ids := alice.page("/v1/m_notes", query)
+	assert.Equal(t, []uint{2201}, ids)
+	_, ids = bob.page("/v1/m_notes", query)
+	assert.Equal(t, []uint{}, ids)
+
+	forbidden := []struct{ method, path, body string }{
+		{"GET", "/v1/m_notes/2201", ""},
+		{"PUT", "/v1/m_notes/2201", `{"id":2201,"title":"taken","content":"c"}`},
+		{"PUT", "/v1/m_notes/delete/2201", ""},
+		{"DELETE", "/v1/m_notes/2201", ""},
+	}
+	for _, r := range forbidden {
+		w = bob.do(r.method, r.path, r.body)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	}
+
+	// notes:all_owners reaches the note of another user
+	query.Set("_allOwners", "true")
+	_, ids = all.page("/v1/m_notes", query)
+	assert.Equal(t, []uint{2201}, ids)
+	w = all.do("GET", "/v1/m_notes/2201?_allOwners=true", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = all.do("GET", "/v1/m_notes/2201", "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = alice.do("GET", "/v1/m_notes/2201", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = alice.do("DELETE", "/v1/m_notes/2201", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
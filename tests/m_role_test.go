@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+
+	"github.com/amsatrio/gin_notes/initializer"
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/model/response"
+)
+
+var seedRolesOnce sync.Once
+
+// seedRoles adds a hierarchy of roles, a lower level being more privileged:
+// HQ_ADMIN (1), HQ_EDITOR (2), HQ_REVIEWER (2), HQ_USER (3) and the soft
+// deleted HQ_GUEST (4). Only HQ_USER is granted a permission.
+func seedRoles() {
+	seedRolesOnce.Do(func() {
+		isDelete := true
+		now := response.JSONTime{Time: time.Now()}
+		roles := []model.MRole{
+			{Id: 101, Code: "HQ_ADMIN", Level: 1, CreatedBy: 1, CreatedOn: now},
+			{Id: 102, Code: "HQ_EDITOR", Level: 2, CreatedBy: 1, CreatedOn: now},
+			{Id: 103, Code: "HQ_REVIEWER", Level: 2, CreatedBy: 1, CreatedOn: now},
+			{Id: 104, Code: "HQ_USER", Level: 3, CreatedBy: 1, CreatedOn: now},
+			{Id: 105, Code: "HQ_GUEST", Level: 4, CreatedBy: 1, CreatedOn: now, IsDelete: &isDelete},
+		}
+		for _, mRole := range roles {
+			if err := initializer.DB.FirstOrCreate(&mRole).Error; err != nil {
+				log.Fatal("Failed to seed roles: " + err.Error())
+			}
+		}
+
+		mPermission := model.MPermission{}
+		if err := initializer.DB.First(&mPermission, "code = ?", "notes:read").Error; err != nil {
+			log.Fatal("Failed to seed roles: " + err.Error())
+		}
+		grant := model.MRolePermission{RoleId: 104, PermissionId: mPermission.Id, CreatedBy: 1, CreatedOn: now}
+		if err := initializer.DB.FirstOrCreate(&grant).Error; err != nil {
+			log.Fatal("Failed to seed roles: " + err.Error())
+		}
+	})
+}
+
+func getMRoleAuthorities(t *testing.T, id string) (int, response.ResponseRoleAuthorities) {
+	router := SetUpRouter()
+	seedRoles()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/m_role/authorities/"+id, nil)
+	router.ServeHTTP(w, req)
+
+	body := struct {
+		Data response.ResponseRoleAuthorities `json:"data"`
+	}{}
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return w.Code, body.Data
+}
+
+func TestMRoleAuthoritiesWithoutHierarchy(t *testing.T) {
+	t.Setenv("AUTH_ROLE_HIERARCHY", "false")
+
+	code, authorities := getMRoleAuthorities(t, "101")
+	assert.Equal(t, 200, code)
+	assert.Equal(t, []string{"HQ_ADMIN"}, authorities.Authorities)
+	assert.Equal(t, []string{}, authorities.Permissions)
+}
+
+func TestMRoleAuthoritiesInheritLowerPrivilege(t *testing.T) {
+	t.Setenv("AUTH_ROLE_HIERARCHY", "true")
+
+	code, authorities := getMRoleAuthorities(t, "101")
+	assert.Equal(t, 200, code)
+	assert.Equal(t, []string{"HQ_ADMIN", "HQ_EDITOR", "HQ_REVIEWER", "HQ_USER"}, authorities.Authorities)
+	assert.Equal(t, []string{"notes:read"}, authorities.Permissions)
+}
+
+func TestMRoleAuthoritiesSkipSameLevel(t *testing.T) {
+	t.Setenv("AUTH_ROLE_HIERARCHY", "true")
+
+	code, authorities := getMRoleAuthorities(t, "102")
+	assert.Equal(t, 200, code)
+	assert.Equal(t, []string{"HQ_EDITOR", "HQ_USER"}, authorities.Authorities)
+}
+
+func TestMRoleAuthoritiesLowestLevel(t *testing.T) {
+	t.Setenv("AUTH_ROLE_HIERARCHY", "true")
+
+	code, authorities := getMRoleAuthorities(t, "104")
+	assert.Equal(t, 200, code)
+	assert.Equal(t, []string{"HQ_USER"}, authorities.Authorities)
+	assert.Equal(t, []string{"notes:read"}, authorities.Permissions)
+}
+
+func TestMRoleAuthoritiesNotFound(t *testing.T) {
+	t.Setenv("AUTH_ROLE_HIERARCHY", "true")
+
+	code, _ := getMRoleAuthorities(t, "999")
+	assert.Equal(t, 400, code)
+}
+
+// TestMRoleCreateGrantsDefaultPermissions creates its role at level 0, out of
+// the roles inherited above.
+func TestMRoleCreateGrantsDefaultPermissions(t *testing.T) {
+	enableAuth(t)
+	router := SetUpAuthRouter()
+	all := login(t, router, "all@example.com")
+
+	w := all.do("POST", "/v1/m_role", `{"id":106,"name":"Writer","code":"HQ_WRITER","level":0}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = all.do("GET", "/v1/m_role/permissions/106", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := struct {
+		Data []model.MPermission `json:"data"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	codes := []string{}
+	for _, mPermission := range body.Data {
+		codes = append(codes, mPermission.Code)
+	}
+	assert.Equal(t, []string{"notes:read", "notes:write"}, codes)
+}
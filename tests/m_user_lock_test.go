@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+
+	"github.com/amsatrio/gin_notes/initializer"
+	"github.com/amsatrio/gin_notes/model"
+)
+
+func TestLoginLocksAfterMaxAttempts(t *testing.T) {
+	enableAuth(t)
+	t.Setenv("AUTH_LOGIN_MAX_ATTEMPT", "3")
+	t.Setenv("AUTH_LOGIN_LOCK_DURATION_MS", "600000")
+	router := SetUpAuthRouter()
+	anonymous := &authSession{router: router}
+	createUser(t, 1401, "locked@example.com")
+
+	code, _, _ := loginAs(anonymous, "locked@example.com", "Wrong1234")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _, _ = loginAs(anonymous, "locked@example.com", "Wrong1234")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _, _ = loginAs(anonymous, "locked@example.com", "Wrong1234")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// only the right password tells the account is locked
+	code, _, _ = loginAs(anonymous, "locked@example.com", "Wrong1234")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _, _ = loginAs(anonymous, "locked@example.com", authPassword)
+	assert.Equal(t, http.StatusLocked, code)
+
+	// an update of the user does not touch the lock
+	all := login(t, router, "all@example.com")
+	w := all.do("PUT", "/v1/m_user/1401", `{"id":1401,"roleId":201,"email":"locked@example.com","isLocked":false,"loginAttempt":0,"MBiodata":{"id":1},"MRole":{"id":201}}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	code, _, _ = loginAs(anonymous, "locked@example.com", authPassword)
+	assert.Equal(t, http.StatusLocked, code)
+
+	w = all.do("PUT", "/v1/m_user/unlock/1401", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	login(t, router, "locked@example.com")
+
+	var mUser model.MUser
+	initializer.DB.First(&mUser, 1401)
+	assert.Equal(t, false, mUser.IsLocked)
+	assert.Equal(t, 0, mUser.LoginAttempt)
+	assert.Equal(t, false, mUser.LastLogin.IsZero())
+}
+
+func TestLoginLockExpires(t *testing.T) {
+	enableAuth(t)
+	t.Setenv("AUTH_LOGIN_MAX_ATTEMPT", "1")
+	t.Setenv("AUTH_LOGIN_LOCK_DURATION_MS", "600000")
+	router := SetUpAuthRouter()
+	anonymous := &authSession{router: router}
+	createUser(t, 1402, "expired@example.com")
+
+	code, _, _ := loginAs(anonymous, "expired@example.com", "Wrong1234")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _, _ = loginAs(anonymous, "expired@example.com", authPassword)
+	assert.Equal(t, http.StatusLocked, code)
+
+	t.Setenv("AUTH_LOGIN_LOCK_DURATION_MS", "50")
+	time.Sleep(100 * time.Millisecond)
+	login(t, router, "expired@example.com")
+}
@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/amsatrio/gin_notes/migration"
+)
+
+// openMigrationDB opens an empty database, the one of Initialize is
+// migrated already.
+func openMigrationDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+func TestMigrationUpAndDown(t *testing.T) {
+	db := openMigrationDB(t)
+	migrations := migration.Migrations()
+
+	done, err := migration.Up(db, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, len(done))
+	assert.Equal(t, migrations[0].Version, done[0].Version)
+
+	done, err = migration.Up(db, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(migrations)-2, len(done))
+	assert.Equal(t, true, db.Migrator().HasTable("t_note_revision"))
+
+	statuses, err := migration.GetStatus(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range statuses {
+		assert.Equal(t, true, status.Applied)
+	}
+
+	// nothing is left to apply
+	done, _ = migration.Up(db, 0)
+	assert.Equal(t, 0, len(done))
+
+	done, err = migration.Down(db, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, migrations[len(migrations)-1].Version, done[0].Version)
+	var count int64
+	db.Table("m_permission").Where("code = ?", "deleted:read").Count(&count)
+	assert.Equal(t, int64(0), count)
+
+	done, err = migration.Down(db, len(migrations))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(migrations)-1, len(done))
+	assert.Equal(t, false, db.Migrator().HasTable("m_biodata"))
+}
+
+func TestMigrationReportsMissingVersions(t *testing.T) {
+	db := openMigrationDB(t)
+	if _, err := migration.Up(db, 1); err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&migration.SchemaMigration{Version: "20000101000000", Name: "removed"})
+
+	statuses, err := migration.GetStatus(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "20000101000000", statuses[0].Version)
+	assert.Equal(t, true, statuses[0].Missing)
+
+	_, err = migration.Down(db, 2)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestMigrationSeedsAdminRole(t *testing.T) {
+	db := openMigrationDB(t)
+	if _, err := migration.Up(db, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	var roleIds []uint
+	db.Table("m_role").Where("code = ?", "ROLE_ADMIN").Pluck("id", &roleIds)
+	if len(roleIds) != 1 {
+		t.Fatalf("ROLE_ADMIN roles: %v", roleIds)
+	}
+
+	var permissions, granted int64
+	db.Table("m_permission").Count(&permissions)
+	db.Table("m_role_permission").Where("role_id = ?", roleIds[0]).Count(&granted)
+	assert.NotEqual(t, int64(0), permissions)
+	assert.Equal(t, permissions, granted)
+}
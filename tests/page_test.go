@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+
+	"github.com/amsatrio/gin_notes/initializer"
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/model/request"
+	"github.com/amsatrio/gin_notes/util"
+)
+
+func countQuery(page string, count string) url.Values {
+	return url.Values{"_filter": {"[" + filterRange + "]"}, "_sort": {`[{"id":"id"}]`}, "_size": {"3"}, "_page": {page}, "_count": {count}}
+}
+
+func TestPageCountsExactly(t *testing.T) {
+	router := SetUpFilterRouter()
+
+	code, page := getPageData(router, "/v1/m_biodata", countQuery("1", "EXACT"))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{1004}, page.ids())
+	assert.Equal(t, int64(4), page.TotalElements)
+	assert.Equal(t, int64(2), page.TotalPages)
+	assert.Equal(t, true, page.Last)
+
+	// sqlite has no table statistics, the estimate falls back to the count
+	code, page = getPageData(router, "/v1/m_biodata", countQuery("0", "estimate"))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int64(4), page.TotalElements)
+	assert.Equal(t, false, page.Last)
+
+	code, page = getPageData(router, "/v1/m_biodata", countQuery("2", "EXACT"))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{}, page.ids())
+}
+
+func TestPageWithoutCount(t *testing.T) {
+	router := SetUpFilterRouter()
+
+	code, page := getPageData(router, "/v1/m_biodata", countQuery("0", "NONE"))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{1001, 1002, 1003}, page.ids())
+	assert.Equal(t, int64(-1), page.TotalElements)
+	assert.Equal(t, int64(-1), page.TotalPages)
+	assert.Equal(t, false, page.Last)
+
+	code, page = getPageData(router, "/v1/m_biodata", countQuery("1", "NONE"))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{1004}, page.ids())
+	assert.Equal(t, true, page.Last)
+
+	code, _ = getPageData(router, "/v1/m_biodata", countQuery("0", "SOMETIMES"))
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = getPageData(router, "/v1/m_biodata", countQuery("-1", "EXACT"))
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+// stubEstimate makes the table statistics of sqlite report estimate rows
+// for the test.
+func stubEstimate(t *testing.T, estimate string) {
+	previous, ok := util.EstimateQueries["sqlite"]
+	util.EstimateQueries["sqlite"] = "SELECT " + estimate + " WHERE ? IS NOT NULL"
+	t.Cleanup(func() {
+		if ok {
+			util.EstimateQueries["sqlite"] = previous
+			return
+		}
+		delete(util.EstimateQueries, "sqlite")
+	})
+}
+
+func TestPageEstimateOnlyCountsWholeTable(t *testing.T) {
+	SetUpFilterRouter()
+	stubEstimate(t, "0")
+	resolver, err := util.NewColumnResolver(initializer.DB, &model.MBiodata{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a filtered query is counted exactly
+	rows := []model.MBiodata{}
+	db := initializer.DB.Model(&model.MBiodata{}).Where("id BETWEEN ? AND ?", 1001, 1004)
+	page, err := util.OffsetPage(db, &rows, resolver, nil, 0, 3, request.COUNT_ESTIMATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, int64(4), page.TotalElements)
+	assert.Equal(t, 3, page.NumberOfElements)
+	assert.Equal(t, false, page.Last)
+
+	// a stale estimate does not hide the rows
+	rows = []model.MBiodata{}
+	page, err = util.OffsetPage(initializer.DB.Model(&model.MBiodata{}), &rows, resolver, nil, 0, 3, request.COUNT_ESTIMATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, int64(0), page.TotalElements)
+	assert.Equal(t, 3, page.NumberOfElements)
+	assert.Equal(t, false, page.Last)
+}
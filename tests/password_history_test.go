@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+)
+
+func TestPasswordHistoryRejectsReuse(t *testing.T) {
+	enableAuth(t)
+	t.Setenv("PASSWORD_HISTORY_SIZE", "2")
+	router := SetUpAuthRouter()
+	anonymous := &authSession{router: router}
+	createUser(t, 1601, "history@example.com")
+
+	token := forgotPassword(t, anonymous, "history@example.com")
+	assert.Equal(t, http.StatusBadRequest, resetPassword(anonymous, token, authPassword))
+	assert.Equal(t, http.StatusBadRequest, resetPassword(anonymous, token, "short"))
+	// a refused password keeps the token
+	assert.Equal(t, http.StatusOK, resetPassword(anonymous, token, "Password2"))
+
+	token = forgotPassword(t, anonymous, "history@example.com")
+	assert.Equal(t, http.StatusBadRequest, resetPassword(anonymous, token, authPassword))
+	assert.Equal(t, http.StatusOK, resetPassword(anonymous, token, "Password3"))
+
+	// the first password left the history of 2
+	token = forgotPassword(t, anonymous, "history@example.com")
+	assert.Equal(t, http.StatusBadRequest, resetPassword(anonymous, token, "Password2"))
+	assert.Equal(t, http.StatusOK, resetPassword(anonymous, token, authPassword))
+}
+
+func TestPasswordHistoryOnUpdate(t *testing.T) {
+	enableAuth(t)
+	router := SetUpAuthRouter()
+	createUser(t, 1602, "update@example.com")
+	all := login(t, router, "all@example.com")
+
+	body := func(password string) string {
+		return `{"id":1602,"roleId":201,"email":"update@example.com","password":"` + password + `","MBiodata":{"id":1},"MRole":{"id":201}}`
+	}
+	assert.Equal(t, http.StatusBadRequest, all.do("PUT", "/v1/m_user/1602", body(authPassword)).Code)
+	assert.Equal(t, http.StatusBadRequest, all.do("PUT", "/v1/m_user/1602", body("nodigits")).Code)
+	assert.Equal(t, http.StatusOK, all.do("PUT", "/v1/m_user/1602", body("Password2")).Code)
+	assert.Equal(t, http.StatusBadRequest, all.do("PUT", "/v1/m_user/1602", body(authPassword)).Code)
+}
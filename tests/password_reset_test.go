@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+
+	"github.com/amsatrio/gin_notes/model/response"
+	"github.com/amsatrio/gin_notes/util"
+)
+
+// recordingMailer keeps the last mail sent to every address.
+type recordingMailer struct {
+	mu    sync.Mutex
+	mails map[string]string
+}
+
+func (m *recordingMailer) Send(to string, subject string, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mails[to] = body
+	return nil
+}
+
+// last returns the last mail sent to to.
+func (m *recordingMailer) last(to string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.mails[to]
+}
+
+// failingMailer fails every mail.
+type failingMailer struct{}
+
+func (failingMailer) Send(to string, subject string, body string) error {
+	return errors.New("mail server is down")
+}
+
+var resetMailer = &recordingMailer{mails: map[string]string{}}
+
+// forgotPassword asks for a reset of email and returns the mailed token. The
+// mail is sent after the reply, it is waited for.
+func forgotPassword(t *testing.T, anonymous *authSession, email string) string {
+	util.SetMailer(resetMailer)
+	previous := resetMailer.last(email)
+	w := anonymous.do("POST", "/v1/auth/forgot_password", `{"email":"`+email+`"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	body := resetMailer.last(email)
+	for deadline := time.Now().Add(5 * time.Second); body == previous && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+		body = resetMailer.last(email)
+	}
+	_, token, _ := strings.Cut(body, "reset your password: ")
+	token, _, _ = strings.Cut(token, "\n")
+	if body == previous || token == "" {
+		t.Fatalf("no reset mail for %s", email)
+	}
+	return token
+}
+
+func resetPassword(anonymous *authSession, token string, password string) int {
+	return anonymous.do("POST", "/v1/auth/reset_password", `{"token":"`+token+`","password":"`+password+`"}`).Code
+}
+
+func TestResetTokenIsSingleUse(t *testing.T) {
+	enableAuth(t)
+	router := SetUpAuthRouter()
+	anonymous := &authSession{router: router}
+	createUser(t, 1501, "reset@example.com")
+
+	first := forgotPassword(t, anonymous, "reset@example.com")
+	token := forgotPassword(t, anonymous, "reset@example.com")
+	// only the last token is valid
+	assert.Equal(t, http.StatusBadRequest, resetPassword(anonymous, first, "Password2"))
+
+	assert.Equal(t, http.StatusOK, resetPassword(anonymous, token, "Password2"))
+	assert.Equal(t, http.StatusBadRequest, resetPassword(anonymous, token, "Password3"))
+
+	code, _, _ := loginAs(anonymous, "reset@example.com", authPassword)
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _, _ = loginAs(anonymous, "reset@example.com", "Password2")
+	assert.Equal(t, http.StatusOK, code)
+
+	// an unknown email looks the same
+	w := anonymous.do("POST", "/v1/auth/forgot_password", `{"email":"nobody@example.com"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestForgotPasswordHidesMailFailure(t *testing.T) {
+	enableAuth(t)
+	router := SetUpAuthRouter()
+	anonymous := &authSession{router: router}
+	createUser(t, 1503, "unmailed@example.com")
+
+	util.SetMailer(failingMailer{})
+	known := anonymous.do("POST", "/v1/auth/forgot_password", `{"email":"unmailed@example.com"}`)
+	unknown := anonymous.do("POST", "/v1/auth/forgot_password", `{"email":"nobody@example.com"}`)
+	assert.Equal(t, http.StatusOK, known.Code)
+	assert.Equal(t, replyOf(t, unknown), replyOf(t, known))
+}
+
+// replyOf returns the status and the message of the reply w.
+func replyOf(t *testing.T, w *httptest.ResponseRecorder) response.Response {
+	reply := response.Response{}
+	if err := json.Unmarshal(w.Body.Bytes(), &reply); err != nil {
+		t.Fatal(err)
+	}
+	return response.Response{Status: reply.Status, Message: reply.Message}
+}
+
+func TestResetTokenExpires(t *testing.T) {
+	enableAuth(t)
+	t.Setenv("AUTH_RESET_TOKEN_EXPIRED_MS", "50")
+	router := SetUpAuthRouter()
+	anonymous := &authSession{router: router}
+	createUser(t, 1502, "late@example.com")
+
+	token := forgotPassword(t, anonymous, "late@example.com")
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, http.StatusBadRequest, resetPassword(anonymous, token, "Password2"))
+	assert.Equal(t, http.StatusBadRequest, resetPassword(anonymous, "not a token", "Password2"))
+}
@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+
+	"github.com/amsatrio/gin_notes/initializer"
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/model/response"
+	"github.com/amsatrio/gin_notes/util"
+)
+
+// loginAs posts the login of email, the session is not kept.
+func loginAs(anonymous *authSession, email string, password string) (int, string, time.Duration) {
+	start := time.Now()
+	w := anonymous.do("POST", "/v1/auth/login", `{"username":"`+email+`","password":"`+password+`"}`)
+	elapsed := time.Since(start)
+
+	body := struct {
+		Message string `json:"message"`
+	}{}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	return w.Code, body.Message, elapsed
+}
+
+func TestLoginRehashesPlaintextPassword(t *testing.T) {
+	enableAuth(t)
+	router := SetUpAuthRouter()
+	mUser := model.MUser{Id: 1301, RoleId: 201, Email: "legacy@example.com", Password: authPassword, CreatedBy: 1, CreatedOn: response.JSONTime{Time: time.Now()}}
+	if err := initializer.DB.Create(&mUser).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	login(t, router, "legacy@example.com")
+	if err := initializer.DB.First(&mUser, 1301).Error; err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, true, strings.HasPrefix(mUser.Password, "$argon2id$"))
+	login(t, router, "legacy@example.com")
+}
+
+func TestLoginOfUnknownUserFailsLikeWrongPassword(t *testing.T) {
+	enableAuth(t)
+	anonymous := &authSession{router: SetUpAuthRouter()}
+
+	wrongCode, wrongMessage, wrongElapsed := loginAs(anonymous, "carol@example.com", "Wrong1234")
+	unknownCode, unknownMessage, unknownElapsed := loginAs(anonymous, "nobody@example.com", "Wrong1234")
+	assert.Equal(t, wrongCode, unknownCode)
+	assert.Equal(t, wrongMessage, unknownMessage)
+
+	// both verify a hash, a lookup alone is orders of magnitude faster
+	if unknownElapsed < wrongElapsed/4 {
+		t.Errorf("unknown user answered in %s, a wrong password in %s", unknownElapsed, wrongElapsed)
+	}
+}
+
+func TestVerifyPasswordAcrossHashers(t *testing.T) {
+	t.Setenv("PASSWORD_HASHER", "bcrypt")
+	t.Setenv("PASSWORD_BCRYPT_COST", "4")
+	hash, err := util.HashPassword(authPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok, rehash, _ := util.VerifyPassword(hash, authPassword)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, false, rehash)
+	ok, _, _ = util.VerifyPassword(hash, "Wrong1234")
+	assert.Equal(t, false, ok)
+
+	// a bcrypt hash is upgraded once argon2id is the hasher again
+	t.Setenv("PASSWORD_HASHER", "argon2id")
+	ok, rehash, _ = util.VerifyPassword(hash, authPassword)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, true, rehash)
+}
@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+)
+
+// permissionPaths are routes and switches each needing a permission the role
+// TEST_NOTES is not granted.
+var permissionPaths = []string{
+	"/v1/m_biodata",
+	"/v1/m_user",
+	"/v1/t_token",
+	"/v1/t_reset_password",
+	"/v1/m_notes?_allOwners=true",
+	"/v1/m_notes?_includeDeleted=true",
+	"/v1/m_notes?_onlyDeleted=true",
+}
+
+func TestPermissionDeniedWithoutGrant(t *testing.T) {
+	enableAuth(t)
+	router := SetUpAuthRouter()
+	alice := login(t, router, "alice@example.com")
+
+	for _, path := range permissionPaths {
+		w := alice.do("GET", path, "")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	}
+
+	w := alice.do("GET", "/v1/m_notes", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestPermissionAllowedWithGrant(t *testing.T) {
+	enableAuth(t)
+	router := SetUpAuthRouter()
+	all := login(t, router, "all@example.com")
+
+	for _, path := range permissionPaths {
+		w := all.do("GET", path, "")
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
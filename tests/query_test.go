@@ -0,0 +1,40 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+
+	"github.com/amsatrio/gin_notes/initializer"
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/util"
+)
+
+func searchBiodata(t *testing.T, search string) []uint {
+	resolver, err := util.NewColumnResolver(initializer.DB, &model.MBiodata{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := initializer.DB.Model(&model.MBiodata{}).Where("id BETWEEN ? AND ?", 1001, 1004).Order("id")
+
+	ids := []uint{}
+	if err := util.ApplyGlobalSearch(db, search, resolver).Pluck("id", &ids).Error; err != nil {
+		t.Fatal(err)
+	}
+	return ids
+}
+
+func TestGlobalSearchIgnoresCase(t *testing.T) {
+	SetUpFilterRouter()
+
+	assert.Equal(t, []uint{1001, 1002}, searchBiodata(t, "DoNe"))
+	assert.Equal(t, []uint{1001, 1002, 1003, 1004}, searchBiodata(t, ""))
+}
+
+func TestGlobalSearchMatchesWildcardsLiterally(t *testing.T) {
+	SetUpFilterRouter()
+
+	assert.Equal(t, []uint{1001}, searchBiodata(t, "0%"))
+	assert.Equal(t, []uint{1003}, searchBiodata(t, "A_B"))
+	assert.Equal(t, []uint{}, searchBiodata(t, `\`))
+}
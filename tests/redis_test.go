@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/amsatrio/gin_notes/initializer"
+)
+
+// fakeRedis answers the few commands of the app over RESP2, enough to test
+// the denylist without a Redis server.
+type fakeRedis struct {
+	mu      sync.Mutex
+	values  map[string]string
+	expires map[string]time.Time
+}
+
+// useFakeRedis points initializer.RDB to a new fakeRedis for the test and
+// turns REDIS_ENABLE on. Call it after Initialize, which sets RDB.
+func useFakeRedis(t *testing.T) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeRedis{values: map[string]string{}, expires: map[string]time.Time{}}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+
+	rdb := initializer.RDB
+	initializer.RDB = redis.NewClient(&redis.Options{Addr: listener.Addr().String(), Protocol: 2})
+	t.Setenv("REDIS_ENABLE", "true")
+	t.Cleanup(func() {
+		initializer.RDB.Close()
+		initializer.RDB = rdb
+		listener.Close()
+	})
+	return f
+}
+
+func (f *fakeRedis) Get(key string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if expire, ok := f.expires[key]; ok && time.Now().After(expire) {
+		delete(f.values, key)
+		delete(f.expires, key)
+	}
+	value, ok := f.values[key]
+	return value, ok
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		if _, err := conn.Write([]byte(f.do(args))); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, count)
+	for i := range args {
+		if line, err = reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
+
+func bulk(value string) string {
+	return "$" + strconv.Itoa(len(value)) + "\r\n" + value + "\r\n"
+}
+
+func (f *fakeRedis) do(args []string) string {
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "CLIENT", "SELECT":
+		return "+OK\r\n"
+	case "SET":
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.values[args[1]] = args[2]
+		delete(f.expires, args[1])
+		for i := 3; i+1 < len(args); i++ {
+			amount, _ := strconv.Atoi(args[i+1])
+			switch strings.ToUpper(args[i]) {
+			case "EX":
+				f.expires[args[1]] = time.Now().Add(time.Duration(amount) * time.Second)
+			case "PX":
+				f.expires[args[1]] = time.Now().Add(time.Duration(amount) * time.Millisecond)
+			}
+		}
+		return "+OK\r\n"
+	case "GET":
+		value, ok := f.Get(args[1])
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulk(value)
+	case "EXISTS", "DEL":
+		count := 0
+		for _, key := range args[1:] {
+			if _, ok := f.Get(key); ok {
+				count++
+				if strings.ToUpper(args[0]) == "DEL" {
+					f.mu.Lock()
+					delete(f.values, key)
+					f.mu.Unlock()
+				}
+			}
+		}
+		return ":" + strconv.Itoa(count) + "\r\n"
+	case "SCAN":
+		pattern := "*"
+		for i := 2; i+1 < len(args); i++ {
+			if strings.ToUpper(args[i]) == "MATCH" {
+				pattern = args[i+1]
+			}
+		}
+		f.mu.Lock()
+		var keys []string
+		for key := range f.values {
+			// the app only scans for prefixes
+			if strings.HasPrefix(key, strings.TrimSuffix(pattern, "*")) {
+				keys = append(keys, bulk(key))
+			}
+		}
+		f.mu.Unlock()
+		return "*2\r\n" + bulk("0") + "*" + strconv.Itoa(len(keys)) + "\r\n" + strings.Join(keys, "")
+	}
+	return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+}
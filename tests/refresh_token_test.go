@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+
+	"github.com/amsatrio/gin_notes/constant"
+	"github.com/amsatrio/gin_notes/initializer"
+	"github.com/amsatrio/gin_notes/service"
+)
+
+func TestRefreshTokenRotates(t *testing.T) {
+	enableAuth(t)
+	router := SetUpAuthRouter()
+	createUser(t, 1701, "rotate@example.com")
+	session := login(t, router, "rotate@example.com")
+	refreshTokenService := service.NewRefreshTokenServiceImpl(initializer.DB)
+
+	next, err := refreshTokenService.Rotate(context.Background(), session.refreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEqual(t, session.refreshToken, next.RefreshToken)
+
+	last, err := refreshTokenService.Rotate(context.Background(), next.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEqual(t, next.RefreshToken, last.RefreshToken)
+
+	_, err = refreshTokenService.Rotate(context.Background(), "not a token")
+	assert.Equal(t, true, errors.Is(err, constant.ErrorTokenInvalid))
+}
+
+func TestRefreshTokenReuseRevokesTheFamily(t *testing.T) {
+	enableAuth(t)
+	router := SetUpAuthRouter()
+	createUser(t, 1702, "reuse@example.com")
+	session := login(t, router, "reuse@example.com")
+	other := login(t, router, "reuse@example.com")
+	refreshTokenService := service.NewRefreshTokenServiceImpl(initializer.DB)
+
+	next, err := refreshTokenService.Rotate(context.Background(), session.refreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, session.do("GET", "/v1/m_notes", "").Code)
+
+	// the used token comes back, it was stolen
+	_, err = refreshTokenService.Rotate(context.Background(), session.refreshToken)
+	assert.Equal(t, true, errors.Is(err, constant.ErrorTokenReused))
+	_, err = refreshTokenService.Rotate(context.Background(), next.RefreshToken)
+	assert.Equal(t, true, errors.Is(err, constant.ErrorTokenInvalid))
+	assert.Equal(t, http.StatusUnauthorized, session.do("GET", "/v1/m_notes", "").Code)
+
+	// another session of the user goes on
+	assert.Equal(t, http.StatusOK, other.do("GET", "/v1/m_notes", "").Code)
+	_, err = refreshTokenService.Rotate(context.Background(), other.refreshToken)
+	assert.Equal(t, nil, err)
+}
@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+)
+
+func TestResourceServesCrudRoutes(t *testing.T) {
+	enableAuth(t)
+	router := SetUpAuthRouter()
+	all := login(t, router, "all@example.com")
+
+	w := all.do("POST", "/v1/m_biodata", `{"id":1201,"fullname":"resource"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = all.do("POST", "/v1/m_biodata", `{"id":1201,"fullname":"again"}`)
+	assert.NotEqual(t, http.StatusOK, w.Code)
+
+	w = all.do("PUT", "/v1/m_biodata/1201", `{"id":1201,"fullname":"renamed"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = all.do("GET", "/v1/m_biodata/1201", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, true, strings.Contains(w.Body.String(), `"fullname":"renamed"`))
+
+	w = all.do("GET", "/v1/m_biodata/header", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, true, strings.Contains(w.Body.String(), `"fullname":"string"`))
+
+	w = all.do("DELETE", "/v1/m_biodata/1201", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = all.do("GET", "/v1/m_biodata/1201", "")
+	assert.NotEqual(t, http.StatusOK, w.Code)
+}
+
+func TestResourceRejectsInvalidRequests(t *testing.T) {
+	enableAuth(t)
+	router := SetUpAuthRouter()
+	all := login(t, router, "all@example.com")
+
+	w := all.do("GET", "/v1/m_biodata/one", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = all.do("POST", "/v1/m_biodata", `{"fullname":"without id"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = all.do("PUT", "/v1/m_biodata/1202", `{"id":1202,"fullname":"missing"}`)
+	assert.NotEqual(t, http.StatusOK, w.Code)
+}
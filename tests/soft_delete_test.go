@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+
+	"github.com/amsatrio/gin_notes/initializer"
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/model/response"
+	"github.com/amsatrio/gin_notes/service"
+)
+
+// softDeleteQuery keeps a page to the rows 1211 and 1212 of the soft delete
+// tests.
+func softDeleteQuery(switches ...string) url.Values {
+	query := url.Values{
+		"_filter": {`[{"id":"id","value":[1211,1212],"matchMode":"BETWEEN","dataType":"NUMBER"}]`},
+		"_sort":   {`[{"id":"id"}]`},
+	}
+	for _, s := range switches {
+		query.Set(s, "true")
+	}
+	return query
+}
+
+// page requests path with query and returns the status and the ids of the
+// page content.
+func (s *authSession) page(path string, query url.Values) (int, []uint) {
+	w := s.do("GET", path+"?"+query.Encode(), "")
+	body := struct {
+		Data pageData `json:"data"`
+	}{}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	return w.Code, body.Data.ids()
+}
+
+func TestSoftDeleteHidesAndRestores(t *testing.T) {
+	enableAuth(t)
+	all := login(t, SetUpAuthRouter(), "all@example.com")
+
+	for _, body := range []string{`{"id":1211,"fullname":"kept"}`, `{"id":1212,"fullname":"trashed"}`} {
+		w := all.do("POST", "/v1/m_biodata", body)
+		assert.Equal(t, 200, w.Code)
+	}
+
+	w := all.do("PUT", "/v1/m_biodata/delete/1212", "")
+	assert.Equal(t, 200, w.Code)
+	w = all.do("GET", "/v1/m_biodata/1212", "")
+	assert.Equal(t, 400, w.Code)
+
+	code, ids := all.page("/v1/m_biodata", softDeleteQuery())
+	assert.Equal(t, 200, code)
+	assert.Equal(t, []uint{1211}, ids)
+	_, ids = all.page("/v1/m_biodata", softDeleteQuery("_onlyDeleted"))
+	assert.Equal(t, []uint{1212}, ids)
+	_, ids = all.page("/v1/m_biodata", softDeleteQuery("_includeDeleted"))
+	assert.Equal(t, []uint{1211, 1212}, ids)
+	code, _ = all.page("/v1/m_biodata", softDeleteQuery("_includeDeleted", "_onlyDeleted"))
+	assert.Equal(t, 400, code)
+
+	w = all.do("PUT", "/v1/m_biodata/restore/1212", "")
+	assert.Equal(t, 200, w.Code)
+	w = all.do("GET", "/v1/m_biodata/1212", "")
+	assert.Equal(t, 200, w.Code)
+	_, ids = all.page("/v1/m_biodata", softDeleteQuery("_onlyDeleted"))
+	assert.Equal(t, []uint{}, ids)
+}
+
+func TestSoftDeletePurgeKeepsRecentRows(t *testing.T) {
+	enableAuth(t)
+	all := login(t, SetUpAuthRouter(), "all@example.com")
+
+	w := all.do("POST", "/v1/m_biodata", `{"id":1213,"fullname":"old"}`)
+	assert.Equal(t, 200, w.Code)
+	w = all.do("PUT", "/v1/m_biodata/delete/1213", "")
+	assert.Equal(t, 200, w.Code)
+
+	// a row deleted after deletedBefore stays in the trash
+	crud := service.NewMBiodataCrudService(initializer.DB)
+	deletedBefore := time.Date(2000, 1, 1, 0, 0, 0, 0, time.Local)
+	if _, err := crud.Purge(context.Background(), deletedBefore); err != nil {
+		t.Fatal(err)
+	}
+	w = all.do("GET", "/v1/m_biodata/1213?_onlyDeleted=true", "")
+	assert.Equal(t, 200, w.Code)
+
+	deletedOn := response.JSONTime{Time: deletedBefore.AddDate(0, 0, -1)}
+	if err := initializer.DB.Model(&model.MBiodata{Id: 1213}).Update("deleted_on", deletedOn).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := crud.Purge(context.Background(), deletedBefore); err != nil {
+		t.Fatal(err)
+	}
+	w = all.do("GET", "/v1/m_biodata/1213?_includeDeleted=true", "")
+	assert.Equal(t, 400, w.Code)
+}
@@ -0,0 +1,32 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+)
+
+func sortQuery(sort string) url.Values {
+	return url.Values{"_filter": {"[" + filterRange + "]"}, "_sort": {sort}}
+}
+
+func TestSortAppliesEveryKey(t *testing.T) {
+	router := SetUpFilterRouter()
+
+	code, ids := getPage(router, "/v1/m_biodata", sortQuery(`[{"id":"createdBy"},{"id":"fullname","desc":true}]`))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{1004, 1003, 1001, 1002}, ids)
+
+	code, ids = getPage(router, "/v1/m_biodata", sortQuery(`[{"id":"id","desc":true}]`))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uint{1004, 1003, 1002, 1001}, ids)
+}
+
+func TestSortRejectsUnknownColumn(t *testing.T) {
+	router := SetUpFilterRouter()
+
+	code, _ := getPage(router, "/v1/m_biodata", sortQuery(`[{"id":"id"},{"id":"nickname"}]`))
+	assert.Equal(t, http.StatusBadRequest, code)
+}
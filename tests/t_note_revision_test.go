@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/model/response"
+)
+
+// revisions returns the revisions of noteId, the latest first.
+func revisions(t *testing.T, s *authSession, noteId string) []model.TNoteRevision {
+	w := s.do("GET", "/v1/m_notes/"+noteId+"/revisions", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("revisions of %s: %d %s", noteId, w.Code, w.Body.String())
+	}
+	body := struct {
+		Data []model.TNoteRevision `json:"data"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	return body.Data
+}
+
+func revisionNumbers(rows []model.TNoteRevision) []uint {
+	numbers := []uint{}
+	for _, row := range rows {
+		numbers = append(numbers, row.Revision)
+	}
+	return numbers
+}
+
+func TestNoteRevisionDiffAndRestore(t *testing.T) {
+	enableAuth(t)
+	t.Setenv("NOTES_REVISION_RETENTION", "0")
+	router := SetUpAuthRouter()
+	alice := login(t, router, "alice@example.com")
+	bob := login(t, router, "bob@example.com")
+
+	w := alice.do("POST", "/v1/m_notes", `{"id":2501,"title":"draft","content":"a\nb"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = alice.do("PUT", "/v1/m_notes/2501", `{"id":2501,"title":"draft","content":"a\nc"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []uint{2, 1}, revisionNumbers(revisions(t, alice, "2501")))
+
+	w = alice.do("GET", "/v1/m_notes/2501/diff?from=1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	diff := struct {
+		Data response.ResponseNoteRevisionDiff `json:"data"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &diff); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint(2), diff.Data.To)
+	assert.Equal(t, []response.DiffLine{
+		{Op: "equal", OldLine: 1, NewLine: 1, Text: "a"},
+		{Op: "delete", OldLine: 2, NewLine: 0, Text: "b"},
+		{Op: "insert", OldLine: 0, NewLine: 2, Text: "c"},
+	}, diff.Data.Content)
+
+	// a restore is a new revision, the old ones stay
+	w = alice.do("POST", "/v1/m_notes/2501/revisions/1/restore", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	rows := revisions(t, alice, "2501")
+	assert.Equal(t, []uint{3, 2, 1}, revisionNumbers(rows))
+	assert.Equal(t, uint(1), rows[0].RestoredFrom)
+	assert.Equal(t, "a\nb", rows[0].Content)
+
+	w = bob.do("GET", "/v1/m_notes/2501/revisions", "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = bob.do("POST", "/v1/m_notes/2501/revisions/1/restore", "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = alice.do("GET", "/v1/m_notes/2501/revisions/9", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestNoteRevisionRetention(t *testing.T) {
+	enableAuth(t)
+	t.Setenv("NOTES_REVISION_RETENTION", "2")
+	router := SetUpAuthRouter()
+	alice := login(t, router, "alice@example.com")
+
+	w := alice.do("POST", "/v1/m_notes", `{"id":2502,"title":"kept","content":"1"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	for _, content := range []string{"2", "3", "4"} {
+		w = alice.do("PUT", "/v1/m_notes/2502", `{"id":2502,"title":"kept","content":"`+content+`"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	// the numbers go on after the dropped revisions
+	assert.Equal(t, []uint{4, 3}, revisionNumbers(revisions(t, alice, "2502")))
+	w = alice.do("GET", "/v1/m_notes/2502/revisions/1", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
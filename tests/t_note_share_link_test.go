@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+
+	"github.com/amsatrio/gin_notes/initializer"
+	"github.com/amsatrio/gin_notes/model"
+	"github.com/amsatrio/gin_notes/model/response"
+)
+
+func createShareLink(t *testing.T, s *authSession, noteId string, body string) response.ResponseNoteShareLink {
+	w := s.do("POST", "/v1/m_notes/"+noteId+"/share_link", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("create share link: %d %s", w.Code, w.Body.String())
+	}
+	link := struct {
+		Data response.ResponseNoteShareLink `json:"data"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &link); err != nil {
+		t.Fatal(err)
+	}
+	return link.Data
+}
+
+// openShareLink reads path without a token, as anyone with the link does.
+func openShareLink(router *gin.Engine, path string, password string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", path, nil)
+	req.Header.Set("Accept", "application/json")
+	if password != "" {
+		req.Header.Set("X-Share-Password", password)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestShareLinkEndsWithItsNote(t *testing.T) {
+	enableAuth(t)
+	router := SetUpAuthRouter()
+	alice := login(t, router, "alice@example.com")
+
+	w := alice.do("POST", "/v1/m_notes", `{"id":2401,"title":"published","content":"c"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	link := createShareLink(t, alice, "2401", `{}`)
+	assert.Equal(t, http.StatusOK, openShareLink(router, link.Path, "").Code)
+
+	w = alice.do("DELETE", "/v1/m_notes/2401", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// a new note of the same id is not published
+	w = alice.do("POST", "/v1/m_notes", `{"id":2401,"title":"private","content":"c"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusBadRequest, openShareLink(router, link.Path, "").Code)
+}
+
+func TestShareLinkPasswordAndAccessCount(t *testing.T) {
+	enableAuth(t)
+	router := SetUpAuthRouter()
+	alice := login(t, router, "alice@example.com")
+	bob := login(t, router, "bob@example.com")
+
+	w := alice.do("POST", "/v1/m_notes", `{"id":2402,"title":"protected","content":"c"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = bob.do("POST", "/v1/m_notes/2402/share_link", `{}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	link := createShareLink(t, alice, "2402", `{"password":"secret1"}`)
+	assert.Equal(t, http.StatusUnauthorized, openShareLink(router, link.Path, "").Code)
+	assert.Equal(t, http.StatusUnauthorized, openShareLink(router, link.Path, "wrong").Code)
+	w = openShareLink(router, link.Path, "secret1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, true, strings.Contains(w.Body.String(), `"title":"protected"`))
+
+	w = alice.do("GET", "/v1/m_notes/2402/share_link", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	links := struct {
+		Data []model.TNoteShareLink `json:"data"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &links); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, len(links.Data))
+	assert.Equal(t, int64(1), links.Data[0].AccessCount)
+	assert.Equal(t, true, links.Data[0].HasPassword)
+}
+
+func TestShareLinkExpiresAndIsRevoked(t *testing.T) {
+	enableAuth(t)
+	router := SetUpAuthRouter()
+	alice := login(t, router, "alice@example.com")
+
+	w := alice.do("POST", "/v1/m_notes", `{"id":2403,"title":"published","content":"c"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = alice.do("POST", "/v1/m_notes/2403/share_link", `{"expiredOn":"2000-01-01 00:00:00"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	expiring := createShareLink(t, alice, "2403", `{"expiredOn":"`+time.Now().Add(time.Hour).Format(response.JSONTimeLayout)+`"}`)
+	assert.Equal(t, http.StatusOK, openShareLink(router, expiring.Path, "").Code)
+	expiredOn := response.JSONTime{Time: time.Now().Add(-time.Minute)}
+	if err := initializer.DB.Model(&model.TNoteShareLink{Id: expiring.Id}).Update("expired_on", expiredOn).Error; err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusBadRequest, openShareLink(router, expiring.Path, "").Code)
+
+	revoked := createShareLink(t, alice, "2403", `{}`)
+	assert.Equal(t, http.StatusOK, openShareLink(router, revoked.Path, "").Code)
+	w = alice.do("DELETE", "/v1/m_notes/2403/share_link/"+strconv.FormatUint(uint64(revoked.Id), 10), "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusBadRequest, openShareLink(router, revoked.Path, "").Code)
+}
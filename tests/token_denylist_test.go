@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+
+	"github.com/amsatrio/gin_notes/constant"
+	"github.com/amsatrio/gin_notes/initializer"
+	"github.com/amsatrio/gin_notes/service"
+	"github.com/amsatrio/gin_notes/util"
+)
+
+func TestDeniedTokenIsRefused(t *testing.T) {
+	enableAuth(t)
+	router := SetUpAuthRouter()
+	useFakeRedis(t)
+	createUser(t, 1801, "denied@example.com")
+	session := login(t, router, "denied@example.com")
+	other := login(t, router, "denied@example.com")
+	assert.Equal(t, http.StatusOK, session.do("GET", "/v1/m_notes", "").Code)
+
+	claims, err := util.JwtExtractAllClaims(session.token, "main_token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = service.NewTokenDenylistServiceImpl(initializer.RDB).Deny(context.Background(), util.JwtGetTokenId(claims), util.JwtGetExpiration(claims))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusUnauthorized, session.do("GET", "/v1/m_notes", "").Code)
+	assert.Equal(t, http.StatusOK, other.do("GET", "/v1/m_notes", "").Code)
+}
+
+func TestPasswordChangeEndsTheSessions(t *testing.T) {
+	enableAuth(t)
+	router := SetUpAuthRouter()
+	redis := useFakeRedis(t)
+	createUser(t, 1802, "changed@example.com")
+	session := login(t, router, "changed@example.com")
+	all := login(t, router, "all@example.com")
+
+	body := func(password string) string {
+		return `{"id":1802,"roleId":201,"email":"changed@example.com","password":"` + password + `","MBiodata":{"id":1},"MRole":{"id":201}}`
+	}
+
+	// a refused change keeps the sessions
+	assert.Equal(t, http.StatusBadRequest, all.do("PUT", "/v1/m_user/1802", body("short")).Code)
+	_, denied := redis.Get(service.DENYLIST_USER_KEY + "changed@example.com")
+	assert.Equal(t, false, denied)
+	assert.Equal(t, http.StatusOK, session.do("GET", "/v1/m_notes", "").Code)
+
+	// the watermark revokes the tokens of the seconds before the change
+	time.Sleep(time.Second)
+	assert.Equal(t, http.StatusOK, all.do("PUT", "/v1/m_user/1802", body("Password2")).Code)
+	_, denied = redis.Get(service.DENYLIST_USER_KEY + "changed@example.com")
+	assert.Equal(t, true, denied)
+	assert.Equal(t, http.StatusUnauthorized, session.do("GET", "/v1/m_notes", "").Code)
+
+	// the refresh token can not mint a new access token
+	_, err := service.NewRefreshTokenServiceImpl(initializer.DB).Rotate(context.Background(), session.refreshToken)
+	assert.Equal(t, true, errors.Is(err, constant.ErrorTokenInvalid))
+
+	assert.Equal(t, http.StatusOK, all.do("GET", "/v1/m_notes", "").Code)
+}
@@ -0,0 +1,98 @@
+package util
+
+import (
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// QueryExcluder is implemented by models that have fields which must never
+// be filtered, sorted or searched (password, token, ...). Names can be the
+// json name, the go field name or the column name.
+type QueryExcluder interface {
+	QueryExcludedFields() []string
+}
+
+// ColumnResolver maps client supplied field names to the declared columns of
+// a model. Only fields parsed by gorm from the model are accepted.
+type ColumnResolver struct {
+	schema *schema.Schema
+	fields map[string]*schema.Field
+}
+
+func NewColumnResolver(db *gorm.DB, value interface{}) (*ColumnResolver, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(value); err != nil {
+		return nil, err
+	}
+
+	excluded := map[string]bool{}
+	if excluder, ok := value.(QueryExcluder); ok {
+		for _, name := range excluder.QueryExcludedFields() {
+			excluded[name] = true
+		}
+	}
+
+	resolver := &ColumnResolver{
+		schema: stmt.Schema,
+		fields: map[string]*schema.Field{},
+	}
+	for _, field := range stmt.Schema.Fields {
+		// relations and ignored fields have no column
+		if field.DBName == "" || !field.Readable {
+			continue
+		}
+
+		names := []string{field.Name, field.DBName}
+		if jsonName := strings.Split(field.Tag.Get("json"), ",")[0]; jsonName != "" && jsonName != "-" {
+			names = append(names, jsonName)
+		}
+
+		isExcluded := false
+		for _, name := range names {
+			if excluded[name] {
+				isExcluded = true
+			}
+		}
+		if isExcluded {
+			continue
+		}
+
+		for _, name := range names {
+			resolver.fields[name] = field
+		}
+	}
+
+	return resolver, nil
+}
+
+// Field returns the field declared for name, or false when name is unknown
+// or excluded.
+func (r *ColumnResolver) Field(name string) (*schema.Field, bool) {
+	field, ok := r.fields[name]
+	return field, ok
+}
+
+// Column returns the table qualified column for name.
+func (r *ColumnResolver) Column(name string) (clause.Column, bool) {
+	field, ok := r.Field(name)
+	if !ok {
+		return clause.Column{}, false
+	}
+	return clause.Column{Table: r.schema.Table, Name: field.DBName}, true
+}
+
+// SearchableColumns returns every text column that is not excluded.
+func (r *ColumnResolver) SearchableColumns() []clause.Column {
+	var columns []clause.Column
+	for _, field := range r.schema.Fields {
+		if r.fields[field.DBName] != field || field.FieldType.Kind() != reflect.String {
+			continue
+		}
+		columns = append(columns, clause.Column{Table: r.schema.Table, Name: field.DBName})
+	}
+	return columns
+}
@@ -0,0 +1,374 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"github.com/amsatrio/gin_notes/model/request"
+	"github.com/amsatrio/gin_notes/model/response"
+)
+
+type keysetColumn struct {
+	field  *schema.Field
+	column clause.Column
+	desc   bool
+}
+
+func (k keysetColumn) nullable() bool {
+	return !k.field.NotNull && !k.field.PrimaryKey
+}
+
+type cursorToken struct {
+	Sort   string        `json:"s"`
+	Values []interface{} `json:"v"`
+}
+
+// CursorPage loads one page of db into dest (a pointer to a slice) using
+// keyset pagination on the sort keys plus the primary key.
+func CursorPage(db *gorm.DB, dest interface{}, resolver *ColumnResolver, sorts []request.Sort, size int, cursor request.Cursor) (*response.Page, error) {
+	queryError := &QueryError{Messages: map[string]string{}}
+
+	if size <= 0 {
+		queryError.add("_size", "must be greater than 0")
+	}
+
+	countMode := cursor.Count
+	if countMode == "" {
+		countMode = request.COUNT_EXACT
+	}
+	if countMode != request.COUNT_EXACT && countMode != request.COUNT_ESTIMATE && countMode != request.COUNT_NONE {
+		queryError.add("_count", "count mode "+countMode.String()+" is not supported")
+	}
+
+	keys := keysetColumns(resolver, sorts, queryError)
+
+	token, tokenName, backward := cursor.After, "_after", false
+	if cursor.Before != "" {
+		if cursor.After != "" {
+			queryError.add("_before", "can not be used together with _after")
+		}
+		token, tokenName, backward = cursor.Before, "_before", true
+	}
+
+	var values []interface{}
+	if token != "" && len(queryError.Messages) == 0 {
+		var err error
+		values, err = decodeCursor(token, keys)
+		if err != nil {
+			queryError.add(tokenName, err.Error())
+		}
+	}
+
+	if len(queryError.Messages) > 0 {
+		Log("INFO", "util", "CursorPage", queryError.Error())
+		return nil, queryError
+	}
+	if db.Error != nil {
+		return nil, db.Error
+	}
+
+	totalElements, err := countElements(db, resolver, countMode)
+	if err != nil {
+		return nil, err
+	}
+
+	query := db
+	if values != nil {
+		query = query.Where(keysetCondition(db, keys, values, backward))
+	}
+	result := query.Order(keysetOrder(keys, backward)).Limit(size + 1).Find(dest)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	rows := reflect.ValueOf(dest).Elem()
+	hasMore := rows.Len() > size
+	if hasMore {
+		rows.Set(rows.Slice(0, size))
+	}
+	if backward {
+		for i, j := 0, rows.Len()-1; i < j; i, j = i+1, j-1 {
+			first, last := rows.Index(i).Interface(), rows.Index(j).Interface()
+			rows.Index(i).Set(reflect.ValueOf(last))
+			rows.Index(j).Set(reflect.ValueOf(first))
+		}
+	}
+
+	var nextCursor, prevCursor string
+	if rows.Len() > 0 {
+		hasNext, hasPrev := hasMore, token != ""
+		if backward {
+			hasNext, hasPrev = true, hasMore
+		}
+		if hasNext {
+			nextCursor = encodeCursor(keys, rows.Index(rows.Len()-1))
+		}
+		if hasPrev {
+			prevCursor = encodeCursor(keys, rows.Index(0))
+		}
+	}
+
+	totalPages := int64(-1)
+	if totalElements >= 0 && size > 0 {
+		totalPages = (totalElements + int64(size) - 1) / int64(size)
+	}
+
+	sort := pageSort(sorts)
+
+	page := response.Page{
+		Content: rows.Interface(),
+		Pageable: response.Pageable{
+			PageSize: size,
+			Paged:    true,
+			UnPaged:  false,
+			Sort:     sort,
+		},
+		Sort:             sort,
+		TotalPages:       totalPages,
+		TotalElements:    totalElements,
+		Size:             size,
+		NumberOfElements: rows.Len(),
+		Last:             nextCursor == "",
+		First:            prevCursor == "",
+		Empty:            rows.Len() == 0,
+		NextCursor:       nextCursor,
+		PrevCursor:       prevCursor,
+	}
+
+	return &page, nil
+}
+
+func keysetColumns(resolver *ColumnResolver, sorts []request.Sort, queryError *QueryError) []keysetColumn {
+	var keys []keysetColumn
+	seen := map[string]bool{}
+
+	for _, sort := range sorts {
+		field, ok := resolver.Field(sort.Id)
+		if !ok {
+			queryError.add(sort.Id, "unknown sort column")
+			continue
+		}
+		if seen[field.DBName] {
+			continue
+		}
+		seen[field.DBName] = true
+		keys = append(keys, keysetColumn{
+			field:  field,
+			column: clause.Column{Table: resolver.schema.Table, Name: field.DBName},
+			desc:   sort.Desc,
+		})
+	}
+
+	primaryField := resolver.schema.PrioritizedPrimaryField
+	if primaryField == nil {
+		queryError.add("_cursor", "cursor pagination needs a primary key")
+		return keys
+	}
+	if !seen[primaryField.DBName] {
+		keys = append(keys, keysetColumn{
+			field:  primaryField,
+			column: clause.Column{Table: resolver.schema.Table, Name: primaryField.DBName},
+		})
+	}
+
+	return keys
+}
+
+// keysetOrder orders NULL values last for ascending keys and first for
+// descending keys on every dialect.
+func keysetOrder(keys []keysetColumn, backward bool) clause.OrderBy {
+	var sql []string
+	var vars []interface{}
+
+	for _, key := range keys {
+		direction := " ASC"
+		if key.desc != backward {
+			direction = " DESC"
+		}
+		if key.nullable() {
+			sql = append(sql, "? IS NULL"+direction)
+			vars = append(vars, key.column)
+		}
+		sql = append(sql, "?"+direction)
+		vars = append(vars, key.column)
+	}
+
+	return clause.OrderBy{Expression: clause.Expr{SQL: strings.Join(sql, ","), Vars: vars}}
+}
+
+// keysetCondition selects the rows after values in the order of keysetOrder:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...
+func keysetCondition(db *gorm.DB, keys []keysetColumn, values []interface{}, backward bool) *gorm.DB {
+	var condition *gorm.DB
+
+	for i, key := range keys {
+		next := newCondition(db)
+		for j := 0; j < i; j++ {
+			if values[j] == nil {
+				next = next.Where("? IS NULL", keys[j].column)
+			} else {
+				next = next.Where("? = ?", keys[j].column, values[j])
+			}
+		}
+
+		desc := key.desc != backward
+		switch {
+		case values[i] == nil && desc:
+			next = next.Where("? IS NOT NULL", key.column)
+		case values[i] == nil:
+			// nothing comes after NULL in ascending order
+			continue
+		case desc:
+			next = next.Where("? < ?", key.column, values[i])
+		case key.nullable():
+			next = next.Where(newCondition(db).Where("? > ?", key.column, values[i]).Or("? IS NULL", key.column))
+		default:
+			next = next.Where("? > ?", key.column, values[i])
+		}
+
+		if condition == nil {
+			condition = newCondition(db).Where(next)
+			continue
+		}
+		condition = condition.Or(next)
+	}
+
+	if condition == nil {
+		return newCondition(db).Where("1 = 0")
+	}
+	return condition
+}
+
+func cursorSignature(keys []keysetColumn) string {
+	signature := make([]string, 0, len(keys))
+	for _, key := range keys {
+		direction := "asc"
+		if key.desc {
+			direction = "desc"
+		}
+		signature = append(signature, key.field.DBName+":"+direction)
+	}
+	return strings.Join(signature, ",")
+}
+
+func encodeCursor(keys []keysetColumn, row reflect.Value) string {
+	token := cursorToken{Sort: cursorSignature(keys)}
+
+	for _, key := range keys {
+		value, _ := key.field.ValueOf(context.Background(), row)
+		if valuer, ok := value.(driver.Valuer); ok {
+			value, _ = valuer.Value()
+		}
+		if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr {
+			value = nil
+			if !rv.IsNil() {
+				value = rv.Elem().Interface()
+			}
+		}
+		if t, ok := value.(time.Time); ok {
+			value = t.Format(time.RFC3339Nano)
+		}
+		token.Values = append(token.Values, value)
+	}
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		LogError("util", "encodeCursor", "marshal cursor failed", err)
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(cursor string, keys []keysetColumn) ([]interface{}, error) {
+	errCursor := errors.New("cursor is invalid")
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errCursor
+	}
+
+	var token cursorToken
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&token); err != nil {
+		return nil, errCursor
+	}
+	if token.Sort != cursorSignature(keys) || len(token.Values) != len(keys) {
+		return nil, errors.New("cursor does not match _sort")
+	}
+
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		if token.Values[i] == nil {
+			values[i] = nil
+			continue
+		}
+		value, err := cursorValue(key.field, token.Values[i])
+		if err != nil {
+			return nil, errCursor
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// cursorValue converts a decoded cursor value back to the type of field.
+func cursorValue(field *schema.Field, value interface{}) (interface{}, error) {
+	fieldType := field.FieldType
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Struct:
+		str, ok := value.(string)
+		if !ok {
+			return nil, errors.New("invalid time")
+		}
+		return time.Parse(time.RFC3339Nano, str)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, ok := value.(json.Number)
+		if !ok {
+			return nil, errors.New("invalid number")
+		}
+		return number.Int64()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		number, ok := value.(json.Number)
+		if !ok {
+			return nil, errors.New("invalid number")
+		}
+		return strconv.ParseUint(number.String(), 10, 64)
+	case reflect.Float32, reflect.Float64:
+		number, ok := value.(json.Number)
+		if !ok {
+			return nil, errors.New("invalid number")
+		}
+		return number.Float64()
+	case reflect.Bool:
+		boolean, ok := value.(bool)
+		if !ok {
+			return nil, errors.New("invalid boolean")
+		}
+		return boolean, nil
+	case reflect.String:
+		str, ok := value.(string)
+		if !ok {
+			return nil, errors.New("invalid text")
+		}
+		return str, nil
+	}
+
+	return nil, errors.New("unsupported cursor column " + field.DBName)
+}
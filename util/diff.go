@@ -0,0 +1,105 @@
+package util
+
+import (
+	"strings"
+
+	"github.com/amsatrio/gin_notes/model/response"
+)
+
+const (
+	DIFF_EQUAL  = "equal"
+	DIFF_INSERT = "insert"
+	DIFF_DELETE = "delete"
+)
+
+// diffMaxCells bounds the n*m table of the longest common subsequence, past
+// it the changed block is diffed as deleted then inserted as a whole.
+const diffMaxCells = 4 << 20
+
+// DiffLines returns the line diff turning oldText into newText, built from
+// the longest common subsequence of their lines.
+func DiffLines(oldText string, newText string) []response.DiffLine {
+	a, b := splitLines(oldText), splitLines(newText)
+
+	// the common head and tail need no table
+	head := 0
+	for head < len(a) && head < len(b) && a[head] == b[head] {
+		head++
+	}
+	tail := 0
+	for tail < len(a)-head && tail < len(b)-head && a[len(a)-1-tail] == b[len(b)-1-tail] {
+		tail++
+	}
+
+	lines := make([]response.DiffLine, 0, len(a)+len(b))
+	for i := 0; i < head; i++ {
+		lines = append(lines, response.DiffLine{Op: DIFF_EQUAL, OldLine: i + 1, NewLine: i + 1, Text: a[i]})
+	}
+	lines = append(lines, diffBlock(a[head:len(a)-tail], b[head:len(b)-tail], head, head)...)
+	for i := 0; i < tail; i++ {
+		oldIndex, newIndex := len(a)-tail+i, len(b)-tail+i
+		lines = append(lines, response.DiffLine{Op: DIFF_EQUAL, OldLine: oldIndex + 1, NewLine: newIndex + 1, Text: a[oldIndex]})
+	}
+	return lines
+}
+
+// diffBlock diffs the lines a and b found at the offsets oldOffset and
+// newOffset of their texts.
+func diffBlock(a []string, b []string, oldOffset int, newOffset int) []response.DiffLine {
+	lines := []response.DiffLine{}
+	if len(a)*len(b) > diffMaxCells {
+		for i, text := range a {
+			lines = append(lines, response.DiffLine{Op: DIFF_DELETE, OldLine: oldOffset + i + 1, Text: text})
+		}
+		for j, text := range b {
+			lines = append(lines, response.DiffLine{Op: DIFF_INSERT, NewLine: newOffset + j + 1, Text: text})
+		}
+		return lines
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, response.DiffLine{Op: DIFF_EQUAL, OldLine: oldOffset + i + 1, NewLine: newOffset + j + 1, Text: a[i]})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			lines = append(lines, response.DiffLine{Op: DIFF_INSERT, NewLine: newOffset + j + 1, Text: b[j]})
+			j++
+		default:
+			lines = append(lines, response.DiffLine{Op: DIFF_DELETE, OldLine: oldOffset + i + 1, Text: a[i]})
+			i++
+		}
+	}
+	return lines
+}
+
+// splitLines splits text on "\n", dropping a "\r" before it. An empty text
+// has no line.
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
@@ -0,0 +1,336 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/amsatrio/gin_notes/constant"
+	"github.com/amsatrio/gin_notes/model/request"
+	"github.com/amsatrio/gin_notes/model/response"
+)
+
+// QueryError is returned when a _filter or _sort request can not be applied.
+// Messages is keyed by the offending filter / sort id.
+type QueryError struct {
+	Messages map[string]string
+}
+
+func (e *QueryError) Error() string {
+	keys := make([]string, 0, len(e.Messages))
+	for key := range e.Messages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	messages := make([]string, 0, len(keys))
+	for _, key := range keys {
+		messages = append(messages, key+": "+e.Messages[key])
+	}
+	return constant.ErrorRequestInvalid.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e *QueryError) Unwrap() error {
+	return constant.ErrorRequestInvalid
+}
+
+func (e *QueryError) add(key string, message string) {
+	if old, ok := e.Messages[key]; ok {
+		message = old + "; " + message
+	}
+	e.Messages[key] = message
+}
+
+// withQueryError attaches err to a new session of db so the following Find
+// fails with it instead of running the query.
+func withQueryError(db *gorm.DB, err *QueryError) *gorm.DB {
+	db = db.Session(&gorm.Session{})
+	_ = db.AddError(err)
+	return db
+}
+
+// maxFilterDepth limits how deep filter groups can be nested.
+const maxFilterDepth = 5
+
+func ApplyFiltering(db *gorm.DB, filter []request.Filter, resolver *ColumnResolver) *gorm.DB {
+	queryError := &QueryError{Messages: map[string]string{}}
+
+	condition := buildFilterGroup(db, filter, request.AND, 0, resolver, queryError)
+
+	if len(queryError.Messages) > 0 {
+		Log("INFO", "util", "ApplyFiltering", queryError.Error())
+		return withQueryError(db, queryError)
+	}
+	if condition == nil {
+		return db
+	}
+	return db.Where(condition)
+}
+
+// buildFilterGroup joins filters left to right into one grouped condition,
+// e.g. [A, B(OR), C(AND)] becomes ((A OR B) AND C). It returns nil when there
+// is nothing to apply.
+func buildFilterGroup(db *gorm.DB, filters []request.Filter, mode request.FilterMode, depth int, resolver *ColumnResolver, queryError *QueryError) *gorm.DB {
+	if mode == "" {
+		mode = request.AND
+	}
+
+	var group *gorm.DB
+	var groupMode request.FilterMode
+
+	for _, condition := range filters {
+		var next *gorm.DB
+		joinMode := mode
+
+		if condition.IsGroup() {
+			if depth >= maxFilterDepth {
+				queryError.add("filters", fmt.Sprintf("filter groups can not be nested more than %d levels", maxFilterDepth))
+				continue
+			}
+			if condition.Mode != "" && condition.Mode != request.AND && condition.Mode != request.OR {
+				queryError.add("filters", "mode "+condition.Mode.String()+" is not supported")
+				continue
+			}
+			next = buildFilterGroup(db, condition.Filters, condition.Mode, depth+1, resolver, queryError)
+			if next == nil {
+				continue
+			}
+		} else {
+			if condition.Mode != "" {
+				if condition.Mode != request.AND && condition.Mode != request.OR {
+					queryError.add(condition.Id, "mode "+condition.Mode.String()+" is not supported")
+					continue
+				}
+				joinMode = condition.Mode
+			}
+			if condition.Id == "" {
+				queryError.add("filters", "id is required")
+				continue
+			}
+			column, ok := resolver.Column(condition.Id)
+			if !ok {
+				queryError.add(condition.Id, "unknown filter column")
+				continue
+			}
+			query, args, err := buildFilterCondition(column, condition)
+			if err != nil {
+				queryError.add(condition.Id, err.Error())
+				continue
+			}
+			next = newCondition(db).Where(query, args...)
+		}
+
+		switch {
+		case group == nil:
+			group = newCondition(db).Where(next)
+			continue
+		case groupMode != "" && groupMode != joinMode:
+			// wrap what we have so far before switching between AND / OR
+			group = newCondition(db).Where(group)
+		}
+
+		if joinMode == request.OR {
+			group = group.Or(next)
+		} else {
+			group = group.Where(next)
+		}
+		groupMode = joinMode
+	}
+
+	return group
+}
+
+// newCondition returns an empty *gorm.DB used to build grouped conditions.
+func newCondition(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true})
+}
+
+func buildFilterCondition(column clause.Column, condition request.Filter) (string, []interface{}, error) {
+	dataType := condition.DataType
+	if dataType == "" {
+		dataType = request.TEXT
+	}
+
+	matchMode := condition.MatchMode
+	if matchMode == "" {
+		matchMode = request.CONTAINS
+		if dataType != request.TEXT {
+			matchMode = request.EQUALS
+		}
+	}
+
+	Log("DEBUG", "util", "buildFilterCondition", "column: "+column.Name+"; matchMode: "+matchMode.String()+"; dataType: "+dataType.String())
+
+	switch matchMode {
+	case request.CONTAINS:
+		if dataType != request.TEXT {
+			return "", nil, fmt.Errorf("match mode %s is not supported for data type %s", matchMode, dataType)
+		}
+		value, err := parseFilterValue(condition.Value, dataType)
+		if err != nil {
+			return "", nil, err
+		}
+		return "? LIKE ? ESCAPE ?", []interface{}{column, "%" + escapeLike(value.(string)) + "%", likeEscape}, nil
+
+	case request.EQUALS, request.NOT:
+		value, err := parseFilterValue(condition.Value, dataType)
+		if err != nil {
+			return "", nil, err
+		}
+		if matchMode == request.NOT {
+			// a NULL column is not equal to the value either
+			return "(? <> ? OR ? IS NULL)", []interface{}{column, value, column}, nil
+		}
+		return "? = ?", []interface{}{column, value}, nil
+
+	case request.LESS_THAN, request.GREATER_THAN:
+		if dataType != request.NUMBER && dataType != request.DATE {
+			return "", nil, fmt.Errorf("match mode %s is not supported for data type %s", matchMode, dataType)
+		}
+		value, err := parseFilterValue(condition.Value, dataType)
+		if err != nil {
+			return "", nil, err
+		}
+		if matchMode == request.LESS_THAN {
+			return "? < ?", []interface{}{column, value}, nil
+		}
+		return "? > ?", []interface{}{column, value}, nil
+
+	case request.BETWEEN:
+		if dataType != request.NUMBER && dataType != request.DATE {
+			return "", nil, fmt.Errorf("match mode %s is not supported for data type %s", matchMode, dataType)
+		}
+		bounds, ok := condition.Value.([]interface{})
+		if !ok || len(bounds) != 2 {
+			return "", nil, errors.New("value must be an array of [from, to]")
+		}
+		if bounds[0] == nil && bounds[1] == nil {
+			return "", nil, errors.New("at least one of from / to is required")
+		}
+
+		var from, to interface{}
+		var err error
+		if bounds[0] != nil {
+			if from, err = parseFilterValue(bounds[0], dataType); err != nil {
+				return "", nil, err
+			}
+		}
+		if bounds[1] != nil {
+			if to, err = parseFilterValue(bounds[1], dataType); err != nil {
+				return "", nil, err
+			}
+		}
+
+		// a date without time ends with its day, compare with the next one
+		toOp := "<="
+		if dataType == request.DATE && isDateOnly(bounds[1]) {
+			to = to.(time.Time).AddDate(0, 0, 1)
+			toOp = "<"
+		}
+
+		// open ended range
+		if from == nil {
+			return "? " + toOp + " ?", []interface{}{column, to}, nil
+		}
+		if to == nil {
+			return "? >= ?", []interface{}{column, from}, nil
+		}
+		if toOp == "<" {
+			return "? >= ? AND ? < ?", []interface{}{column, from, column, to}, nil
+		}
+		return "? BETWEEN ? AND ?", []interface{}{column, from, to}, nil
+	}
+
+	return "", nil, fmt.Errorf("match mode %s is not supported", matchMode)
+}
+
+// likeEscape is the escape character of the LIKE patterns. It is bound as a
+// parameter, mysql reads a '\' literal as an escaped quote.
+const likeEscape = `\`
+
+var likeReplacer = strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_")
+
+// escapeLike escapes value so a LIKE ... ESCAPE likeEscape matches it literally.
+func escapeLike(value string) string {
+	return likeReplacer.Replace(value)
+}
+
+// isDateOnly reports whether value is a date filter value without a time.
+func isDateOnly(value interface{}) bool {
+	str, ok := value.(string)
+	if !ok {
+		return false
+	}
+	_, err := time.ParseInLocation(time.DateOnly, strings.TrimSpace(str), time.Local)
+	return err == nil
+}
+
+// parseFilterValue converts a decoded JSON value into the go type of dataType.
+func parseFilterValue(value interface{}, dataType request.FilterDataType) (interface{}, error) {
+	if value == nil {
+		return nil, errors.New("value is required")
+	}
+
+	switch dataType {
+	case request.TEXT:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case float64, bool:
+			return fmt.Sprintf("%v", v), nil
+		}
+		return nil, errors.New("value must be a text")
+
+	case request.NUMBER:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case json.Number:
+			return v.Float64()
+		case string:
+			number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, errors.New("value must be a number")
+			}
+			return number, nil
+		}
+		return nil, errors.New("value must be a number")
+
+	case request.DATE:
+		str, ok := value.(string)
+		if !ok {
+			return nil, errors.New("value must be a date with format " + response.JSONTimeLayout)
+		}
+		str = strings.TrimSpace(str)
+		date, err := time.ParseInLocation(response.JSONTimeLayout, str, time.Local)
+		if err != nil {
+			date, err = time.ParseInLocation(time.DateOnly, str, time.Local)
+		}
+		if err != nil {
+			return nil, errors.New("value must be a date with format " + response.JSONTimeLayout)
+		}
+		return date, nil
+
+	case request.BOOLEAN:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			boolean, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return nil, errors.New("value must be a boolean")
+			}
+			return boolean, nil
+		}
+		return nil, errors.New("value must be a boolean")
+	}
+
+	return nil, fmt.Errorf("data type %s is not supported", dataType)
+}
@@ -10,32 +10,33 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func generateClaims(username string, authorities []string, claims_type string) (jwt.MapClaims, error) {
-
-	var tokenExpiredString = os.Getenv("AUTH_JWT_TOKEN_EXPIRED_MS")
+func generateClaims(username string, authorities []string, permissions []string, sessionId string, claims_type string) (jwt.MapClaims, error) {
 
 	var claims jwt.MapClaims = jwt.MapClaims{}
 
 	claims["authorities"] = authorities
+	claims["permissions"] = permissions
 	claims["isAccountNonExpired"] = true
 	claims["isAccountNonLocked"] = true
 	claims["isCredentialsNonExpired"] = true
 	claims["isEnabled"] = true
 	claims["sub"] = username
-	now := time.Now()
-	claims["iat"] = now.Unix()
-	tokenExpired, err := strconv.ParseInt(tokenExpiredString, 10, 64)
+	claims["sid"] = sessionId
+	jti, err := GenerateToken()
 	if err != nil {
-		LogError("middleware", "generateClaims", "parse exp env error", err)
-		tokenExpired = 86400000
+		return nil, err
 	}
+	claims["jti"] = jti
+	now := time.Now()
+	claims["iat"] = now.Unix()
+	tokenExpired := JwtMainTokenLifetime()
 
 	claims["type"] = claims_type
 
 	if claims_type == "main_token" {
-		claims["exp"] = now.Add(time.Duration(tokenExpired) * time.Millisecond).Unix()
+		claims["exp"] = now.Add(tokenExpired).Unix()
 	} else if claims_type == "refresh_token" {
-		claims["exp"] = now.Add(time.Duration(tokenExpired*3) * time.Millisecond).Unix()
+		claims["exp"] = now.Add(tokenExpired * 3).Unix()
 	} else {
 		return nil, errors.New("claims_token invalid")
 	}
@@ -43,21 +44,35 @@ func generateClaims(username string, authorities []string, claims_type string) (
 	return claims, nil
 }
 
-func JwtGenerateMainToken(username string, authorities []string) (string, error) {
-	var secretKey = []byte(os.Getenv("AUTH_JWT_TOKEN_SECRET"))
+// JwtMainTokenLifetime is AUTH_JWT_TOKEN_EXPIRED_MS, 1 day by default. A
+// refresh token lives three times as long.
+func JwtMainTokenLifetime() time.Duration {
+	tokenExpired, err := strconv.ParseInt(os.Getenv("AUTH_JWT_TOKEN_EXPIRED_MS"), 10, 64)
+	if err != nil {
+		LogError("util", "JwtMainTokenLifetime", "parse exp env error", err)
+		tokenExpired = 86400000
+	}
+	return time.Duration(tokenExpired) * time.Millisecond
+}
 
+// JwtGenerateMainToken signs an access token of the session (refresh token
+// family) sessionId.
+func JwtGenerateMainToken(username string, authorities []string, permissions []string, sessionId string) (string, error) {
 	// claims (payload)
-	claims, err := generateClaims(username, authorities, "main_token")
+	claims, err := generateClaims(username, authorities, permissions, sessionId, "main_token")
 	if err != nil {
 		LogError("util", "GenerateJWT", "generate claims failed", err)
 		return "", err
 	}
 
-	// new jwt
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	keyring, err := GetJwtKeyring()
+	if err != nil {
+		LogError("util", "GenerateJWT", "load keys failed", err)
+		return "", err
+	}
 
 	// sign
-	tokenString, err := token.SignedString(secretKey)
+	tokenString, err := keyring.Sign(claims)
 
 	if err != nil {
 		LogError("util", "GenerateJWT", "sign token failed", err)
@@ -67,21 +82,24 @@ func JwtGenerateMainToken(username string, authorities []string) (string, error)
 	return tokenString, nil
 }
 
-func JwtGenerateRefreshToken(username string, authorities []string) (string, error) {
-	var secretKey = []byte(os.Getenv("AUTH_JWT_TOKEN_SECRET"))
-
+// JwtGenerateRefreshToken signs a refresh token of the session sessionId. Every
+// token gets a unique jti so two tokens of the same second differ.
+func JwtGenerateRefreshToken(username string, authorities []string, permissions []string, sessionId string) (string, error) {
 	// claims (payload)
-	claims, err := generateClaims(username, authorities, "refresh_token")
+	claims, err := generateClaims(username, authorities, permissions, sessionId, "refresh_token")
 	if err != nil {
 		LogError("util", "GenerateJWT", "generate claims failed", err)
 		return "", err
 	}
 
-	// new jwt
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	keyring, err := GetJwtKeyring()
+	if err != nil {
+		LogError("util", "GenerateJWT", "load keys failed", err)
+		return "", err
+	}
 
 	// sign
-	tokenString, err := token.SignedString(secretKey)
+	tokenString, err := keyring.Sign(claims)
 
 	if err != nil {
 		LogError("util", "GenerateJWT", "sign token failed", err)
@@ -93,14 +111,13 @@ func JwtGenerateRefreshToken(username string, authorities []string) (string, err
 
 func JwtExtractAllClaims(tokenJwt string, tokenType string) (jwt.MapClaims, error) {
 
-	token, err := jwt.Parse(tokenJwt, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("there was an error in parsing")
-		}
+	keyring, err := GetJwtKeyring()
+	if err != nil {
+		LogError("util", "ExtractAllClaims", "load keys failed", err)
+		return nil, err
+	}
 
-		var secretKey = []byte(os.Getenv("AUTH_JWT_TOKEN_SECRET"))
-		return secretKey, nil
-	})
+	token, err := jwt.Parse(tokenJwt, keyring.Keyfunc)
 
 	if err != nil {
 		LogError("util", "ExtractAllClaims", "parse token failed", err)
@@ -139,6 +156,21 @@ func JwtGetAuthorities(claims jwt.MapClaims) []string {
 	return authorities
 }
 
+// JwtGetPermissions returns the permissions claim, empty for a token issued
+// before permissions.
+func JwtGetPermissions(claims jwt.MapClaims) []string {
+	permissionsInterface, _ := claims["permissions"].([]interface{})
+
+	permissions := make([]string, 0, len(permissionsInterface))
+	for _, v := range permissionsInterface {
+		if permission, ok := v.(string); ok {
+			permissions = append(permissions, permission)
+		}
+	}
+
+	return permissions
+}
+
 func JwtGetUserName(claims jwt.MapClaims) string {
 	subject, err := claims.GetSubject()
 	if err != nil {
@@ -148,3 +180,35 @@ func JwtGetUserName(claims jwt.MapClaims) string {
 
 	return subject
 }
+
+// JwtGetSessionId returns the sid claim, empty for a token issued before
+// sessions were tracked.
+func JwtGetSessionId(claims jwt.MapClaims) string {
+	sessionId, _ := claims["sid"].(string)
+	return sessionId
+}
+
+// JwtGetExpiration returns the exp claim.
+func JwtGetExpiration(claims jwt.MapClaims) time.Time {
+	expiration, err := claims.GetExpirationTime()
+	if err != nil || expiration == nil {
+		return time.Time{}
+	}
+	return expiration.Time
+}
+
+// JwtGetTokenId returns the jti claim, empty for a token issued before tokens
+// had one.
+func JwtGetTokenId(claims jwt.MapClaims) string {
+	tokenId, _ := claims["jti"].(string)
+	return tokenId
+}
+
+// JwtGetIssuedAt returns the iat claim.
+func JwtGetIssuedAt(claims jwt.MapClaims) time.Time {
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		return time.Time{}
+	}
+	return issuedAt.Time
+}
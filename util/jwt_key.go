@@ -0,0 +1,223 @@
+package util
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JwtKey is a key of a JwtKeyring. SignKey is nil for a key that only
+// verifies, like the previous key during a rotation.
+type JwtKey struct {
+	Id        string
+	Method    jwt.SigningMethod
+	SignKey   crypto.PrivateKey
+	VerifyKey crypto.PublicKey
+}
+
+// JwtKeyring signs tokens with its Signing key and verifies them with any of
+// its keys, found by the kid header.
+type JwtKeyring struct {
+	Signing *JwtKey
+	Keys    map[string]*JwtKey
+}
+
+var (
+	jwtKeyring     *JwtKeyring
+	jwtKeyringErr  error
+	jwtKeyringOnce sync.Once
+)
+
+// GetJwtKeyring returns the keyring of LoadJwtKeyring, loaded on the first
+// call.
+func GetJwtKeyring() (*JwtKeyring, error) {
+	jwtKeyringOnce.Do(func() {
+		jwtKeyring, jwtKeyringErr = LoadJwtKeyring()
+	})
+	return jwtKeyring, jwtKeyringErr
+}
+
+// SetJwtKeyring replaces the keyring of GetJwtKeyring.
+func SetJwtKeyring(keyring *JwtKeyring) {
+	jwtKeyringOnce.Do(func() {})
+	jwtKeyring, jwtKeyringErr = keyring, nil
+}
+
+// LoadJwtKeyring builds the keyring of AUTH_JWT_ALGORITHM:
+//   - HS256 (default) signs with AUTH_JWT_TOKEN_SECRET, its tokens have no kid
+//   - RS256 and EdDSA sign with the PEM private key of
+//     AUTH_JWT_PRIVATE_KEY_FILE
+//
+// AUTH_JWT_PUBLIC_KEY_FILES is a comma separated list of PEM public keys
+// accepted too, so tokens of the previous key stay valid after a rotation and
+// the next key can be published before it is used. The kid of a key is its
+// RFC 7638 thumbprint.
+func LoadJwtKeyring() (*JwtKeyring, error) {
+	keyring := &JwtKeyring{Keys: map[string]*JwtKey{}}
+
+	algorithm := os.Getenv("AUTH_JWT_ALGORITHM")
+	switch algorithm {
+	case "", jwt.SigningMethodHS256.Alg():
+		secret := []byte(os.Getenv("AUTH_JWT_TOKEN_SECRET"))
+		keyring.Signing = &JwtKey{Method: jwt.SigningMethodHS256, SignKey: secret, VerifyKey: secret}
+	case jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg():
+		signing, err := loadJwtPrivateKey(os.Getenv("AUTH_JWT_PRIVATE_KEY_FILE"))
+		if err != nil {
+			return nil, err
+		}
+		if signing.Method.Alg() != algorithm {
+			return nil, fmt.Errorf("AUTH_JWT_PRIVATE_KEY_FILE is not a %s key", algorithm)
+		}
+		keyring.Signing = signing
+	default:
+		return nil, fmt.Errorf("AUTH_JWT_ALGORITHM %s is not supported", algorithm)
+	}
+	keyring.Keys[keyring.Signing.Id] = keyring.Signing
+
+	for _, path := range strings.Split(os.Getenv("AUTH_JWT_PUBLIC_KEY_FILES"), ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		key, err := loadJwtPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := keyring.Keys[key.Id]; !ok {
+			keyring.Keys[key.Id] = key
+		}
+	}
+
+	return keyring, nil
+}
+
+// Sign signs claims with the signing key.
+func (k *JwtKeyring) Sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(k.Signing.Method, claims)
+	if k.Signing.Id != "" {
+		token.Header["kid"] = k.Signing.Id
+	}
+	return token.SignedString(k.Signing.SignKey)
+}
+
+// Keyfunc returns the verification key of the kid of token to jwt.Parse. The
+// algorithm of the token has to be the one of the key.
+func (k *JwtKeyring) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.Keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("key %q is unknown", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("key %q is not a %s key", kid, token.Method.Alg())
+	}
+	return key.VerifyKey, nil
+}
+
+// Jwks returns the public keys as a JSON Web Key Set. An HS256 secret is never
+// published.
+func (k *JwtKeyring) Jwks() map[string]interface{} {
+	ids := make([]string, 0, len(k.Keys))
+	for id := range k.Keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	keys := []map[string]string{}
+	for _, id := range ids {
+		key := k.Keys[id]
+		jwk := publicJwk(key.VerifyKey)
+		if jwk == nil {
+			continue
+		}
+		jwk["kid"] = key.Id
+		jwk["alg"] = key.Method.Alg()
+		jwk["use"] = "sig"
+		keys = append(keys, jwk)
+	}
+	return map[string]interface{}{"keys": keys}
+}
+
+func loadJwtPrivateKey(path string) (*JwtKey, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := jwt.ParseRSAPrivateKeyFromPEM(pem); err == nil {
+		return newJwtKey(jwt.SigningMethodRS256, key, &key.PublicKey)
+	}
+	if key, err := jwt.ParseEdPrivateKeyFromPEM(pem); err == nil {
+		return newJwtKey(jwt.SigningMethodEdDSA, key, key.(ed25519.PrivateKey).Public())
+	}
+	return nil, errors.New(path + " is not an RSA or Ed25519 private key")
+}
+
+func loadJwtPublicKey(path string) (*JwtKey, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(pem); err == nil {
+		return newJwtKey(jwt.SigningMethodRS256, nil, key)
+	}
+	if key, err := jwt.ParseEdPublicKeyFromPEM(pem); err == nil {
+		return newJwtKey(jwt.SigningMethodEdDSA, nil, key)
+	}
+	return nil, errors.New(path + " is not an RSA or Ed25519 public key")
+}
+
+func newJwtKey(method jwt.SigningMethod, signKey crypto.PrivateKey, verifyKey crypto.PublicKey) (*JwtKey, error) {
+	jwk := publicJwk(verifyKey)
+	if jwk == nil {
+		return nil, errors.New("key type is not supported")
+	}
+
+	// the thumbprint hashes the required members in lexicographic order,
+	// which is the order json.Marshal writes a map in
+	thumbprint, err := json.Marshal(jwk)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(thumbprint)
+
+	return &JwtKey{
+		Id:        base64.RawURLEncoding.EncodeToString(sum[:]),
+		Method:    method,
+		SignKey:   signKey,
+		VerifyKey: verifyKey,
+	}, nil
+}
+
+// publicJwk returns the required JWK members of a public key, nil for a
+// secret.
+func publicJwk(key crypto.PublicKey) map[string]string {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return map[string]string{
+			"kty": "RSA",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}
+	case ed25519.PublicKey:
+		return map[string]string{
+			"kty": "OKP",
+			"crv": "Ed25519",
+			"x":   base64.RawURLEncoding.EncodeToString(key),
+		}
+	}
+	return nil
+}
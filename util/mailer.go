@@ -0,0 +1,138 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mailer sends a plain text mail.
+type Mailer interface {
+	Send(to string, subject string, body string) error
+}
+
+// SMTPMailer sends through the SMTP server of MAIL_SMTP_HOST:MAIL_SMTP_PORT
+// with PLAIN auth when MAIL_SMTP_USERNAME is set.
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (m *SMTPMailer) Send(to string, subject string, body string) error {
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(subject, "\r\n") {
+		return errors.New("mail header must not contain a line break")
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+
+	message := "From: " + m.From + "\r\n" +
+		"To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Date: " + time.Now().Format(time.RFC1123Z) + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" + body
+	return smtp.SendMail(net.JoinHostPort(m.Host, m.Port), auth, m.From, []string{to}, []byte(message))
+}
+
+// FileMailer appends the mails to Path instead of sending them, for
+// development and tests.
+type FileMailer struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func (m *FileMailer) Send(to string, subject string, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(m.Path), os.ModePerm); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(m.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n", time.Now().Format(time.RFC1123Z), to, subject, body)
+	if err != nil {
+		return err
+	}
+	Log("INFO", "util", "FileMailer", "mail to "+to+" written to "+m.Path)
+	return nil
+}
+
+var (
+	mailer     Mailer
+	mailerOnce sync.Once
+)
+
+// DisabledMailer refuses every mail, GetMailer returns it when no mailer is
+// configured.
+type DisabledMailer struct{}
+
+func (DisabledMailer) Send(to string, subject string, body string) error {
+	return errors.New("mail is disabled, set MAIL_DRIVER to smtp or file")
+}
+
+// GetMailer returns the mailer of MAIL_DRIVER:
+//   - smtp sends through SMTPMailer
+//   - file writes to MAIL_FILE_PATH, log/mail.log by default. The reset
+//     tokens end up in plain text in it, so it is for development only and
+//     the default only when GIN_MODE is debug or test
+//
+// Any other MAIL_DRIVER disables the mails.
+func GetMailer() Mailer {
+	mailerOnce.Do(func() {
+		driver := os.Getenv("MAIL_DRIVER")
+		if driver == "" {
+			if mode := os.Getenv("GIN_MODE"); mode != "debug" && mode != "test" {
+				Log("ERROR", "util", "GetMailer", "MAIL_DRIVER is not set, no mail is sent")
+				mailer = DisabledMailer{}
+				return
+			}
+			driver = "file"
+		}
+
+		switch driver {
+		case "smtp":
+			mailer = &SMTPMailer{
+				Host:     os.Getenv("MAIL_SMTP_HOST"),
+				Port:     os.Getenv("MAIL_SMTP_PORT"),
+				Username: os.Getenv("MAIL_SMTP_USERNAME"),
+				Password: os.Getenv("MAIL_SMTP_PASSWORD"),
+				From:     os.Getenv("MAIL_FROM"),
+			}
+		case "file":
+			path := os.Getenv("MAIL_FILE_PATH")
+			if path == "" {
+				path = filepath.Join("log", "mail.log")
+			}
+			Log("ERROR", "util", "GetMailer", "mails are written to "+path+" with their reset tokens in plain text, never use the file driver in production")
+			mailer = &FileMailer{Path: path}
+		default:
+			Log("ERROR", "util", "GetMailer", "MAIL_DRIVER "+driver+" is not supported, no mail is sent")
+			mailer = DisabledMailer{}
+		}
+	})
+	return mailer
+}
+
+// SetMailer replaces the mailer returned by GetMailer, e.g. in tests.
+func SetMailer(m Mailer) {
+	mailerOnce.Do(func() {})
+	mailer = m
+}
@@ -0,0 +1,154 @@
+package util
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/amsatrio/gin_notes/model/request"
+	"github.com/amsatrio/gin_notes/model/response"
+)
+
+// Paginate loads one page of db into dest (a pointer to a slice). It uses
+// keyset pagination when cursor is enabled, LIMIT / OFFSET otherwise.
+func Paginate(db *gorm.DB, dest interface{}, resolver *ColumnResolver, sorts []request.Sort, pageInt int, sizeInt int, cursor request.Cursor) (*response.Page, error) {
+	if cursor.Enabled {
+		return CursorPage(db, dest, resolver, sorts, sizeInt, cursor)
+	}
+	return OffsetPage(db, dest, resolver, sorts, pageInt, sizeInt, cursor.Count)
+}
+
+// OffsetPage loads page pageInt of db into dest. The total is counted with a
+// single COUNT(*) on a copy of db, before sorting and paginating.
+func OffsetPage(db *gorm.DB, dest interface{}, resolver *ColumnResolver, sorts []request.Sort, pageInt int, sizeInt int, countMode request.CountMode) (*response.Page, error) {
+	queryError := &QueryError{Messages: map[string]string{}}
+	if pageInt < 0 {
+		queryError.add("_page", "must not be negative")
+	}
+	if sizeInt <= 0 {
+		queryError.add("_size", "must be greater than 0")
+	}
+	if countMode == "" {
+		countMode = request.COUNT_EXACT
+	}
+	if countMode != request.COUNT_EXACT && countMode != request.COUNT_ESTIMATE && countMode != request.COUNT_NONE {
+		queryError.add("_count", "count mode "+countMode.String()+" is not supported")
+	}
+	if len(queryError.Messages) > 0 {
+		Log("INFO", "util", "OffsetPage", queryError.Error())
+		return nil, queryError
+	}
+
+	// apply sorting, the primary key keeps the order stable between pages
+	db = ApplySorting(db, sorts, resolver)
+	if db.Error != nil {
+		return nil, db.Error
+	}
+	if primaryField := resolver.schema.PrioritizedPrimaryField; primaryField != nil {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Table: resolver.schema.Table, Name: primaryField.DBName}})
+	}
+
+	totalElements, err := countElements(db, resolver, countMode)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := pageInt * sizeInt
+	rows := reflect.ValueOf(dest).Elem()
+
+	// the total may be an estimate, one more row tells whether a next page
+	// exists
+	result := db.Offset(offset).Limit(sizeInt + 1).Find(dest)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	hasMore := rows.Len() > sizeInt
+	if hasMore {
+		rows.Set(rows.Slice(0, sizeInt))
+	}
+	if rows.IsNil() {
+		rows.Set(reflect.MakeSlice(rows.Type(), 0, 0))
+	}
+
+	totalPages := int64(-1)
+	if totalElements >= 0 {
+		totalPages = (totalElements + int64(sizeInt) - 1) / int64(sizeInt)
+	}
+
+	sort := pageSort(sorts)
+
+	page := response.Page{
+		Content: rows.Interface(),
+		Pageable: response.Pageable{
+			Offset:     offset,
+			PageNumber: pageInt,
+			PageSize:   sizeInt,
+			Paged:      true,
+			UnPaged:    false,
+			Sort:       sort,
+		},
+		Sort:             sort,
+		TotalPages:       totalPages,
+		TotalElements:    totalElements,
+		Size:             sizeInt,
+		Number:           pageInt,
+		NumberOfElements: rows.Len(),
+		Last:             !hasMore,
+		First:            pageInt == 0,
+		Empty:            rows.Len() == 0,
+	}
+
+	return &page, nil
+}
+
+func pageSort(sorts []request.Sort) response.Sort {
+	return response.Sort{
+		Empty:    len(sorts) == 0,
+		Sorted:   len(sorts) > 0,
+		Unsorted: len(sorts) == 0,
+	}
+}
+
+// EstimateQueries read the row count estimate of a table per dialect, the
+// other dialects count exactly.
+var EstimateQueries = map[string]string{
+	"mysql":    "SELECT table_rows FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?",
+	"postgres": "SELECT reltuples::bigint FROM pg_class WHERE oid = to_regclass(?)",
+}
+
+// countElements returns -1 when mode is COUNT_NONE. COUNT_ESTIMATE uses the
+// table statistics when the dialect has them, which count the whole table:
+// a query with a WHERE condition is counted exactly.
+func countElements(db *gorm.DB, resolver *ColumnResolver, mode request.CountMode) (int64, error) {
+	if mode == request.COUNT_NONE {
+		return -1, nil
+	}
+
+	var total int64
+	if mode == request.COUNT_ESTIMATE && !hasWhere(db) {
+		if query, ok := EstimateQueries[db.Dialector.Name()]; ok {
+			result := db.Session(&gorm.Session{NewDB: true}).Raw(query, resolver.schema.Table).Scan(&total)
+			if result.Error == nil && result.RowsAffected > 0 && total >= 0 {
+				return total, nil
+			}
+		}
+		Log("INFO", "util", "countElements", "estimate is not available, fallback to exact count")
+	}
+
+	model := reflect.New(resolver.schema.ModelType).Interface()
+	if err := db.Session(&gorm.Session{}).Model(model).Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// hasWhere reports whether db has a WHERE condition.
+func hasWhere(db *gorm.DB) bool {
+	where, ok := db.Statement.Clauses["WHERE"]
+	if !ok {
+		return false
+	}
+	expression, ok := where.Expression.(clause.Where)
+	return !ok || len(expression.Exprs) > 0
+}
@@ -0,0 +1,222 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordHasher hashes passwords into a self-describing string, so a hash
+// made by one hasher can still be verified after PASSWORD_HASHER changes.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Verify reports whether password matches hash, hash was made by this
+	// hasher.
+	Verify(hash string, password string) (bool, error)
+	// Match reports whether hash was made by this hasher.
+	Match(hash string) bool
+	// NeedsRehash reports whether hash was made with weaker parameters than
+	// the current ones.
+	NeedsRehash(hash string) bool
+}
+
+// Argon2idHasher writes hashes in the PHC format
+// $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>.
+type Argon2idHasher struct {
+	Time    uint32
+	Memory  uint32 // KiB
+	Threads uint8
+	KeyLen  uint32
+	SaltLen uint32
+}
+
+type argon2idParams struct {
+	version uint32
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+func NewArgon2idHasher() *Argon2idHasher {
+	return &Argon2idHasher{
+		Time:    3,
+		Memory:  64 * 1024,
+		Threads: 2,
+		KeyLen:  32,
+		SaltLen: 16,
+	}
+}
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Time, h.Memory, h.Threads, h.KeyLen)
+
+	encoding := base64.RawStdEncoding
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.Memory, h.Time, h.Threads,
+		encoding.EncodeToString(salt), encoding.EncodeToString(key)), nil
+}
+
+func (h *Argon2idHasher) Verify(hash string, password string) (bool, error) {
+	params, err := parseArgon2id(hash)
+	if err != nil {
+		return false, err
+	}
+	key := argon2.IDKey([]byte(password), params.salt, params.time, params.memory, params.threads, uint32(len(params.key)))
+	return subtle.ConstantTimeCompare(key, params.key) == 1, nil
+}
+
+func (h *Argon2idHasher) Match(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$")
+}
+
+func (h *Argon2idHasher) NeedsRehash(hash string) bool {
+	params, err := parseArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	return params.version != argon2.Version ||
+		params.memory < h.Memory ||
+		params.time < h.Time ||
+		params.threads < h.Threads ||
+		uint32(len(params.key)) < h.KeyLen
+}
+
+func parseArgon2id(hash string) (*argon2idParams, error) {
+	// "", "argon2id", "v=19", "m=65536,t=3,p=2", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, errors.New("invalid argon2id hash")
+	}
+
+	params := &argon2idParams{}
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &params.version); err != nil {
+		return nil, errors.New("invalid argon2id hash version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return nil, errors.New("invalid argon2id hash parameters")
+	}
+
+	var err error
+	encoding := base64.RawStdEncoding
+	if params.salt, err = encoding.DecodeString(parts[4]); err != nil {
+		return nil, errors.New("invalid argon2id hash salt")
+	}
+	if params.key, err = encoding.DecodeString(parts[5]); err != nil || len(params.key) == 0 {
+		return nil, errors.New("invalid argon2id hash key")
+	}
+	return params, nil
+}
+
+type BcryptHasher struct {
+	Cost int
+}
+
+func NewBcryptHasher() *BcryptHasher {
+	cost, err := strconv.Atoi(os.Getenv("PASSWORD_BCRYPT_COST"))
+	if err != nil || cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptHasher{Cost: cost}
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (h *BcryptHasher) Verify(hash string, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (h *BcryptHasher) Match(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost < h.Cost
+}
+
+// NewPasswordHasher returns the hasher of PASSWORD_HASHER, argon2id (default)
+// or bcrypt.
+func NewPasswordHasher() PasswordHasher {
+	if strings.ToLower(os.Getenv("PASSWORD_HASHER")) == "bcrypt" {
+		return NewBcryptHasher()
+	}
+	return NewArgon2idHasher()
+}
+
+func HashPassword(password string) (string, error) {
+	return NewPasswordHasher().Hash(password)
+}
+
+// VerifyPassword checks password against the stored value, which is either a
+// hash of a known hasher or a legacy plaintext password. rehash is set when
+// the password matches but the stored value should be replaced by
+// HashPassword.
+func VerifyPassword(stored string, password string) (ok bool, rehash bool, err error) {
+	current := NewPasswordHasher()
+	hashers := []PasswordHasher{current, NewArgon2idHasher(), NewBcryptHasher()}
+	for _, hasher := range hashers {
+		if !hasher.Match(stored) {
+			continue
+		}
+		ok, err := hasher.Verify(stored, password)
+		if err != nil || !ok {
+			return false, false, err
+		}
+		return true, !current.Match(stored) || current.NeedsRehash(stored), nil
+	}
+
+	// legacy plaintext row
+	if stored == "" {
+		return false, false, nil
+	}
+	ok = subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+	return ok, ok, nil
+}
+
+// dummyHashes caches a hash per hasher setting for VerifyDummyPassword.
+var dummyHashes sync.Map
+
+// VerifyDummyPassword verifies password against a hash of the current hasher
+// and discards the result. A login of an unknown user calls it so it takes as
+// long as a wrong password.
+func VerifyDummyPassword(password string) {
+	hasher := NewPasswordHasher()
+	key := fmt.Sprintf("%#v", hasher)
+	hash, ok := dummyHashes.Load(key)
+	if !ok {
+		value, err := hasher.Hash("dummy password")
+		if err != nil {
+			return
+		}
+		hash, _ = dummyHashes.LoadOrStore(key, value)
+	}
+	_, _ = hasher.Verify(hash.(string), password)
+}
@@ -0,0 +1,114 @@
+package util
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// PasswordPolicy is the rule set of a new password. HistorySize and MaxAge
+// need the stored passwords of the user, they are applied by the services.
+type PasswordPolicy struct {
+	MinLength        int
+	MaxLength        int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	// HistorySize is the number of last passwords that can not be reused.
+	HistorySize int
+	// MaxAge is how long a password is valid, 0 for ever.
+	MaxAge time.Duration
+}
+
+// NewPasswordPolicy reads the policy from the environment:
+//
+//	PASSWORD_MIN_LENGTH          8
+//	PASSWORD_MAX_LENGTH          64
+//	PASSWORD_REQUIRE_UPPERCASE   true
+//	PASSWORD_REQUIRE_LOWERCASE   true
+//	PASSWORD_REQUIRE_DIGIT       true
+//	PASSWORD_REQUIRE_SYMBOL      false
+//	PASSWORD_HISTORY_SIZE        5
+//	PASSWORD_MAX_AGE_DAYS        0
+func NewPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{
+		MinLength:        envInt("PASSWORD_MIN_LENGTH", 8),
+		MaxLength:        envInt("PASSWORD_MAX_LENGTH", 64),
+		RequireUppercase: envBool("PASSWORD_REQUIRE_UPPERCASE", true),
+		RequireLowercase: envBool("PASSWORD_REQUIRE_LOWERCASE", true),
+		RequireDigit:     envBool("PASSWORD_REQUIRE_DIGIT", true),
+		RequireSymbol:    envBool("PASSWORD_REQUIRE_SYMBOL", false),
+		HistorySize:      envInt("PASSWORD_HISTORY_SIZE", 5),
+		MaxAge:           time.Duration(envInt("PASSWORD_MAX_AGE_DAYS", 0)) * 24 * time.Hour,
+	}
+}
+
+// Check returns a *FieldError on field listing every rule password breaks,
+// nil when it follows the policy.
+func (p *PasswordPolicy) Check(field string, password string) error {
+	var messages []string
+
+	length := len([]rune(password))
+	if length < p.MinLength {
+		messages = append(messages, "minimum "+strconv.Itoa(p.MinLength)+" character(s)")
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		messages = append(messages, "maximum "+strconv.Itoa(p.MaxLength)+" character(s)")
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if p.RequireUppercase && !upper {
+		messages = append(messages, "should contain an uppercase letter")
+	}
+	if p.RequireLowercase && !lower {
+		messages = append(messages, "should contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		messages = append(messages, "should contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		messages = append(messages, "should contain a symbol")
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+	return &FieldError{Messages: map[string]string{field: strings.Join(messages, "; ")}}
+}
+
+// Expired reports whether a password changed on changedOn is older than
+// MaxAge.
+func (p *PasswordPolicy) Expired(changedOn time.Time) bool {
+	return p.MaxAge > 0 && time.Since(changedOn) > p.MaxAge
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
+
+func envBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
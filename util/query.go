@@ -1,85 +1,91 @@
 package util
 
 import (
-	"fmt"
 	"reflect"
-	"regexp"
+	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/amsatrio/gin_notes/model/request"
 )
 
-func ApplyPaginate(pageInt int, limitInt int) func(db *gorm.DB) *gorm.DB {
-	return func(db *gorm.DB) *gorm.DB {
-		offset := 0
-		if pageInt >= 0 {
-			offset = (pageInt) * limitInt
-		}
-		return db.Offset(offset).Limit(limitInt)
-	}
-}
+func ApplySorting(db *gorm.DB, sorts []request.Sort, resolver *ColumnResolver) *gorm.DB {
+	queryError := &QueryError{Messages: map[string]string{}}
 
-func ApplySorting(db *gorm.DB, sorts []request.Sort) *gorm.DB {
 	for _, sort := range sorts {
-		if sort.Desc {
-			return db.Order(sort.Id + " DESC")
+		column, ok := resolver.Column(sort.Id)
+		if !ok {
+			queryError.add(sort.Id, "unknown sort column")
+			continue
 		}
-		return db.Order(sort.Id + " ASC")
-	}
-	return db
-}
 
-func ApplyFiltering(db *gorm.DB, filter []request.Filter) *gorm.DB {
-	for _, condition := range filter {
-		key, value, operation := condition.Id, condition.Value, condition.MatchMode
-
-		valueString := ""
-
-		if str, ok := value.(string); ok {
-			fmt.Println("key: " + key + "; value: " + str + "; operation: " + operation.String())
-			valueString = str
-		}
+		db = db.Order(clause.OrderByColumn{
+			Column: column,
+			Desc:   sort.Desc,
+		})
+	}
 
-		switch operation {
-		case request.EQUALS:
-			db = db.Where(key+" = ?", value)
-		default:
-			db = db.Where(key+" LIKE ?", "%"+valueString+"%")
-		}
+	if len(queryError.Messages) > 0 {
+		Log("INFO", "util", "ApplySorting", queryError.Error())
+		return withQueryError(db, queryError)
 	}
 	return db
 }
 
-func ApplyGlobalSearch(db *gorm.DB, search string, modelMap map[string]string) *gorm.DB {
+func ApplyGlobalSearch(db *gorm.DB, search string, resolver *ColumnResolver) *gorm.DB {
 	if search == "" {
 		Log("INFO", "util", "ApplyGlobalSearch", "search is empty")
 		return db
 	}
 
-	regex := regexp.MustCompile(`[^a-zA-Z0-9\s]+`)
-
-	// cleansing search for security
-	search = regex.ReplaceAllString(search, "")
+	like := likeInsensitive(db)
+	pattern := "%" + escapeLike(strings.ToLower(search)) + "%"
 
-	searchQuery := ""
-	for key, value := range modelMap {
-		// Log("INFO", "util", "ApplyGlobalSearch", "key: "+key+", value: "+value)
-		if value != "string" {
+	var searchQuery *gorm.DB
+	for _, column := range resolver.SearchableColumns() {
+		if searchQuery == nil {
+			searchQuery = newCondition(db).Where(like, column, pattern, likeEscape)
 			continue
 		}
+		searchQuery = searchQuery.Or(like, column, pattern, likeEscape)
+	}
+	if searchQuery == nil {
+		return db
+	}
+	return db.Where(searchQuery)
+}
 
-		key = CamelCaseToSnakeCase(key)
+// ApplyDeletedFilter hides or selects the soft-deleted rows, mode defaults to
+// DELETED_EXCLUDE. Models without an IsDelete field are not filtered.
+func ApplyDeletedFilter(db *gorm.DB, mode request.DeletedMode, resolver *ColumnResolver) *gorm.DB {
+	column, ok := resolver.Column("IsDelete")
+	if !ok {
+		return db
+	}
 
-		if searchQuery != "" {
-			searchQuery = searchQuery + " or "
-		}
-		searchQuery = searchQuery + key + " like " + "'%" + search + "%'"
+	switch mode {
+	case "", request.DELETED_EXCLUDE:
+		return db.Where(newCondition(db).Where("? IS NULL", column).Or("? = ?", column, false))
+	case request.DELETED_ONLY:
+		return db.Where("? = ?", column, true)
+	case request.DELETED_INCLUDE:
+		return db
 	}
-	if search != "" {
-		db = db.Where(searchQuery)
+
+	queryError := &QueryError{Messages: map[string]string{}}
+	queryError.add("_deleted", "mode "+mode.String()+" is not supported")
+	return withQueryError(db, queryError)
+}
+
+// likeInsensitive returns a case-insensitive LIKE condition for the dialect of
+// db. The pattern must be lower case and escaped with escapeLike.
+func likeInsensitive(db *gorm.DB) string {
+	if db.Dialector.Name() == "postgres" {
+		return "? ILIKE ? ESCAPE ?"
 	}
-	return db
+	// LIKE of mysql depends on the column collation, sqlite folds ASCII only
+	return "LOWER(?) LIKE ? ESCAPE ?"
 }
 
 func GetJSONFieldTypes(s interface{}) map[string]string {
@@ -2,8 +2,12 @@ package util
 
 import (
 	"errors"
+	"sort"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
+
+	"github.com/amsatrio/gin_notes/constant"
 )
 
 func ValidateError(err error) (map[string]string, error) {
@@ -50,7 +54,50 @@ func getErrorMessage(fieldError validator.FieldError) string {
 		return "should be greater than field " + fieldError.Param()
 	case "ltfield":
 		return "should be less than field " + fieldError.Param()
+	case "oneof":
+		return "should be one of " + fieldError.Param()
 	default:
 		return fieldError.Tag()
 	}
 }
+
+func ValidateQueryError(err error) map[string]string {
+	var qe *QueryError
+	if errors.As(err, &qe) {
+		return qe.Messages
+	}
+	return nil
+}
+
+// FieldError is returned by a service for a request that breaks a rule the
+// binding tags can not express, e.g. the password policy. Messages are keyed
+// by field like the ones of ValidateError.
+type FieldError struct {
+	Messages map[string]string
+}
+
+func (e *FieldError) Error() string {
+	keys := make([]string, 0, len(e.Messages))
+	for key := range e.Messages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	messages := make([]string, 0, len(keys))
+	for _, key := range keys {
+		messages = append(messages, key+": "+e.Messages[key])
+	}
+	return constant.ErrorRequestInvalid.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e *FieldError) Unwrap() error {
+	return constant.ErrorRequestInvalid
+}
+
+func ValidateFieldError(err error) map[string]string {
+	var fe *FieldError
+	if errors.As(err, &fe) {
+		return fe.Messages
+	}
+	return nil
+}